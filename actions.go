@@ -0,0 +1,254 @@
+package finder
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/pkg/errors"
+)
+
+// Action is operation applied to matched files by Finder.Apply. path is absolute path of file, rel is path relative
+// to glob base (directory part of pattern).
+type Action interface {
+	// Describe returns description of what Apply does, used for dry run and confirmation
+	Describe(path, rel string) string
+	Apply(info file.FileInfoEx, path, rel string) error
+}
+
+// ActionOptions configures Finder.Apply
+type ActionOptions struct {
+	// DryRun reports what would be done without applying action
+	DryRun bool
+	// Output receives description of every action (prefixed with "would " in dry run). Optional.
+	Output io.Writer
+	// Confirm is asked before action is applied to file, false skips file. Calls are serialized so hook may prompt
+	// user. Optional, not called in dry run.
+	Confirm func(info file.FileInfoEx, description string) bool
+}
+
+// ActionResult is outcome of action for single file
+type ActionResult struct {
+	Path        string
+	Description string
+	DryRun      bool
+	Skipped     bool
+	Err         error
+}
+
+// ActionReport is result of Finder.Apply. Results are sorted by path.
+type ActionReport struct {
+	Results []ActionResult
+	Applied int
+	Skipped int
+	Failed  int
+}
+
+// Apply streams files matched by pattern to action using checker pool. Failure for one file doesn't stop others, it's
+// recorded in report. Returned error is reserved for glob failures.
+func (f *Finder) Apply(pattern string, action Action, opts ActionOptions) (report *ActionReport, err error) {
+	var base string
+	if base, err = filepath.Abs(globBase(pattern)); err != nil {
+		err = errors.Wrap(err, "filepath.Abs")
+		return
+	}
+	var (
+		resultsMu sync.Mutex
+		outputMu  sync.Mutex
+		confirmMu sync.Mutex
+	)
+	report = &ActionReport{}
+	err = f.GlobEach(pattern, func(info file.FileInfoEx) {
+		path := fileInfoExPath(info)
+		result := ActionResult{Path: path, DryRun: opts.DryRun}
+		rel, relErr := filepath.Rel(base, path)
+		if relErr != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			result.Err = errors.Errorf("%s is outside of glob base %s", path, base)
+		} else {
+			result.Description = action.Describe(path, rel)
+			switch {
+			case opts.DryRun:
+			case opts.Confirm != nil && !confirmed(&confirmMu, opts.Confirm, info, result.Description):
+				result.Skipped = true
+			default:
+				result.Err = action.Apply(info, path, rel)
+			}
+		}
+		if opts.Output != nil && result.Err == nil && !result.Skipped {
+			outputMu.Lock()
+			if opts.DryRun {
+				fmt.Fprintln(opts.Output, "would "+result.Description)
+			} else {
+				fmt.Fprintln(opts.Output, result.Description)
+			}
+			outputMu.Unlock()
+		}
+		resultsMu.Lock()
+		report.Results = append(report.Results, result)
+		resultsMu.Unlock()
+	})
+	if err != nil {
+		return
+	}
+	sort.Slice(report.Results, func(i, j int) bool {
+		return report.Results[i].Path < report.Results[j].Path
+	})
+	for _, result := range report.Results {
+		switch {
+		case result.Err != nil:
+			report.Failed++
+		case result.Skipped:
+			report.Skipped++
+		default:
+			report.Applied++
+		}
+	}
+	return
+}
+
+func confirmed(mu *sync.Mutex, confirm func(file.FileInfoEx, string) bool, info file.FileInfoEx, description string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return confirm(info, description)
+}
+
+type deleteAction struct{}
+
+// DeleteAction removes matched files. Directories are removed only if they are empty.
+func DeleteAction() Action {
+	return deleteAction{}
+}
+
+func (deleteAction) Describe(path, rel string) string {
+	return "delete " + path
+}
+
+func (deleteAction) Apply(info file.FileInfoEx, path, rel string) error {
+	return errors.Wrap(os.Remove(path), "delete")
+}
+
+type transferAction struct {
+	destDir string
+	move    bool
+}
+
+// MoveAction moves matched files into destDir preserving their path relative to glob base. Existing files aren't
+// overwritten. Files are copied and removed if rename isn't possible (e.g. across filesystems). Matched directories
+// are created at destination but not removed from source.
+func MoveAction(destDir string) Action {
+	return transferAction{destDir: destDir, move: true}
+}
+
+// CopyAction copies matched files into destDir preserving their path relative to glob base, permission bits and
+// modification time. Existing files aren't overwritten. Matched directories are created at destination.
+func CopyAction(destDir string) Action {
+	return transferAction{destDir: destDir}
+}
+
+func (a transferAction) Describe(path, rel string) string {
+	verb := "copy"
+	if a.move {
+		verb = "move"
+	}
+	return fmt.Sprintf("%s %s to %s", verb, path, filepath.Join(a.destDir, rel))
+}
+
+func (a transferAction) Apply(info file.FileInfoEx, path, rel string) (err error) {
+	dest := filepath.Join(a.destDir, rel)
+	if info.IsDir() {
+		return errors.Wrap(os.MkdirAll(dest, info.Mode().Perm()|0700), "mkdir")
+	}
+	if _, err = os.Lstat(dest); err == nil {
+		return errors.Errorf("destination %s already exists", dest)
+	}
+	if err = os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	if a.move {
+		if err = os.Rename(path, dest); err == nil || !isCrossDevice(err) {
+			return errors.Wrap(err, "rename")
+		}
+	}
+	if err = copyFile(path, dest, info); err != nil {
+		return
+	}
+	if a.move {
+		err = errors.Wrap(os.Remove(path), "remove source")
+	}
+	return
+}
+
+func isCrossDevice(err error) bool {
+	linkErr, ok := err.(*os.LinkError)
+	return ok && linkErr.Err == syscall.EXDEV
+}
+
+func copyFile(src, dest string, info file.FileInfoEx) (err error) {
+	var in, out *os.File
+	if in, err = os.Open(src); err != nil {
+		return errors.Wrap(err, "open source")
+	}
+	defer in.Close()
+	// O_EXCL guards against file created since existence check
+	if out, err = os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm()); err != nil {
+		return errors.Wrap(err, "create destination")
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return errors.Wrap(err, "copy")
+	}
+	if err = out.Close(); err != nil {
+		os.Remove(dest)
+		return errors.Wrap(err, "close destination")
+	}
+	return errors.Wrap(os.Chtimes(dest, info.ModTime(), info.ModTime()), "chtimes")
+}
+
+type chmodAction struct {
+	mode os.FileMode
+}
+
+// ChmodAction changes permission bits of matched files
+func ChmodAction(mode os.FileMode) Action {
+	return chmodAction{mode}
+}
+
+func (a chmodAction) Describe(path, rel string) string {
+	return fmt.Sprintf("chmod %#o %s", a.mode.Perm(), path)
+}
+
+func (a chmodAction) Apply(info file.FileInfoEx, path, rel string) error {
+	return errors.Wrap(os.Chmod(path, a.mode), "chmod")
+}
+
+type touchAction struct {
+	t time.Time
+}
+
+// TouchAction sets access and modification time of matched files. Zero time means time of applying action.
+func TouchAction(t time.Time) Action {
+	return touchAction{t}
+}
+
+func (a touchAction) Describe(path, rel string) string {
+	if a.t.IsZero() {
+		return "touch " + path
+	}
+	return fmt.Sprintf("touch %s to %s", path, a.t.Format(time.RFC3339))
+}
+
+func (a touchAction) Apply(info file.FileInfoEx, path, rel string) error {
+	t := a.t
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return errors.Wrap(os.Chtimes(path, t, t), "touch")
+}
@@ -0,0 +1,110 @@
+package finder
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func createActionsTree(t *testing.T) string {
+	return createTestTree(t, map[string]string{
+		"src/a.txt":     "a",
+		"src/sub/b.txt": "b",
+		"src/c.log":     "c",
+	})
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestFinder_Apply(t *testing.T) {
+	t.Run("DryRun", func(t *testing.T) {
+		dir := createActionsTree(t)
+		output := &bytes.Buffer{}
+		report, err := New().SetCheckerConcurrency(1).RegexpName(`\.txt$`).
+			Apply(dir+"/src/**", DeleteAction(), ActionOptions{DryRun: true, Output: output})
+		assert.NoError(t, err)
+		assert.Equal(t, "would delete "+dir+"/src/a.txt\nwould delete "+dir+"/src/sub/b.txt\n", output.String())
+		assert.Equal(t, 2, report.Applied)
+		assert.True(t, report.Results[0].DryRun)
+		assert.True(t, fileExists(filepath.Join(dir, "src/a.txt")))
+	})
+	t.Run("DeleteWithConfirmation", func(t *testing.T) {
+		dir := createActionsTree(t)
+		var asked []string
+		report, err := New().RegexpName(`\.txt$`).Apply(dir+"/src/**", DeleteAction(), ActionOptions{
+			Confirm: func(info file.FileInfoEx, description string) bool {
+				asked = append(asked, description)
+				return info.Name() == "a.txt"
+			},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, asked, 2)
+		assert.Equal(t, &ActionReport{
+			Results: []ActionResult{
+				{Path: dir + "/src/a.txt", Description: "delete " + dir + "/src/a.txt"},
+				{Path: dir + "/src/sub/b.txt", Description: "delete " + dir + "/src/sub/b.txt", Skipped: true},
+			},
+			Applied: 1,
+			Skipped: 1,
+		}, report)
+		assert.False(t, fileExists(filepath.Join(dir, "src/a.txt")))
+		assert.True(t, fileExists(filepath.Join(dir, "src/sub/b.txt")))
+	})
+	t.Run("CopyPreservesStructure", func(t *testing.T) {
+		dir := createActionsTree(t)
+		dest := filepath.Join(dir, "dest")
+		if err := os.MkdirAll(dest, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dest, "a.txt"), []byte("existing"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		os.Chtimes(filepath.Join(dir, "src/sub/b.txt"), modTime, modTime)
+		report, err := New().RegexpName(`\.txt$`).Apply(dir+"/src/**", CopyAction(dest), ActionOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Applied)
+		assert.Equal(t, 1, report.Failed)
+		assert.Error(t, report.Results[0].Err)
+		existing, _ := ioutil.ReadFile(filepath.Join(dest, "a.txt"))
+		assert.Equal(t, "existing", string(existing))
+		copied, err := os.Stat(filepath.Join(dest, "sub/b.txt"))
+		if assert.NoError(t, err) {
+			assert.True(t, copied.ModTime().Equal(modTime))
+		}
+		assert.True(t, fileExists(filepath.Join(dir, "src/sub/b.txt")))
+	})
+	t.Run("Move", func(t *testing.T) {
+		dir := createActionsTree(t)
+		dest := filepath.Join(dir, "dest")
+		report, err := New().Apply(dir+"/src/**/*.txt", MoveAction(dest), ActionOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Applied)
+		assert.True(t, fileExists(filepath.Join(dest, "a.txt")))
+		assert.True(t, fileExists(filepath.Join(dest, "sub/b.txt")))
+		assert.False(t, fileExists(filepath.Join(dir, "src/sub/b.txt")))
+		assert.True(t, fileExists(filepath.Join(dir, "src/c.log")))
+	})
+	t.Run("ChmodAndTouch", func(t *testing.T) {
+		dir := createActionsTree(t)
+		path := filepath.Join(dir, "src/c.log")
+		_, err := New().Apply(path, ChmodAction(0600), ActionOptions{})
+		assert.NoError(t, err)
+		touchTime := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+		report, err := New().Apply(path, TouchAction(touchTime), ActionOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "touch "+path+" to 2021-02-03T04:05:06Z", report.Results[0].Description)
+		stat, _ := os.Stat(path)
+		assert.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+		assert.True(t, stat.ModTime().Equal(touchTime))
+	})
+}
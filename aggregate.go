@@ -0,0 +1,243 @@
+package finder
+
+import (
+	"fmt"
+	"io"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/duffpl/go-finder/mimechecker"
+	"github.com/pkg/errors"
+)
+
+// Grouping assigns result item to groups of aggregation. base is directory part of glob pattern (absolute path).
+// Item may belong to more than one group, e.g. to every ancestor directory.
+type Grouping func(base string, fiex file.FileInfoEx) (keys []string, err error)
+
+// AggregateEntry holds totals of single group
+type AggregateEntry struct {
+	Key string
+	// Parent is key of parent directory for ByDirectory grouping, empty otherwise
+	Parent string
+	Count  int
+	// Size is sum of apparent sizes
+	Size int64
+	// Allocated is sum of bytes allocated on disk, hard linked inode is counted once. Items without stat data (non
+	// Linux platforms) count as 0
+	Allocated int64
+}
+
+// AggregateSortField is field that aggregation entries can be sorted by
+type AggregateSortField string
+
+const (
+	SortByKey       AggregateSortField = "key"
+	SortByCount     AggregateSortField = "count"
+	SortBySize      AggregateSortField = "size"
+	SortByAllocated AggregateSortField = "allocated"
+)
+
+// AggregateReport is result of Aggregate. Entries are sorted by key unless Sort is called.
+type AggregateReport struct {
+	Entries []AggregateEntry
+	// Total sums all matched items, every item is counted once
+	Total AggregateEntry
+}
+
+// ByDirectory groups items by directory relative to glob base, like du --max-depth. Item is counted in its directory
+// and every ancestor up to base, directories deeper than depth are folded into their ancestor at depth. Base
+// directory has key ".", items outside of base (e.g. reached through symlinked directory) are counted only there.
+func ByDirectory(depth int) Grouping {
+	return func(base string, fiex file.FileInfoEx) (keys []string, err error) {
+		var abs, rel string
+		if abs, err = fiex.Abs(); err != nil {
+			return
+		}
+		keys = []string{"."}
+		if rel, err = filepath.Rel(base, filepath.Dir(abs)); err != nil || rel == "." || rel == ".." ||
+			strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return keys, nil
+		}
+		parts := strings.Split(rel, string(filepath.Separator))
+		for i := 1; i <= len(parts) && i <= depth; i++ {
+			keys = append(keys, filepath.Join(parts[:i]...))
+		}
+		return
+	}
+}
+
+// ByExtension groups items by lower cased file extension. Files without extension have empty key.
+func ByExtension() Grouping {
+	return func(base string, fiex file.FileInfoEx) ([]string, error) {
+		return []string{strings.ToLower(filepath.Ext(fiex.Name()))}, nil
+	}
+}
+
+// ByMime groups items by media type (without parameters). Items of unknown type have empty key.
+func ByMime() Grouping {
+	return func(base string, fiex file.FileInfoEx) (keys []string, err error) {
+		var mimeResult *mimechecker.Result
+		if mimeResult, err = file.MimeResult(fiex); err != nil {
+			return
+		}
+		return []string{mimeResult.MediaType}, nil
+	}
+}
+
+// ByOwner groups items by owner user name. Numeric uid is used if user can't be looked up, empty key if stat data
+// isn't available.
+func ByOwner() Grouping {
+	var mu sync.Mutex
+	names := map[uint32]string{}
+	return func(base string, fiex file.FileInfoEx) ([]string, error) {
+		stat, ok := file.StatOf(fiex)
+		if !ok {
+			return []string{""}, nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		name, found := names[stat.Uid]
+		if !found {
+			name = strconv.FormatUint(uint64(stat.Uid), 10)
+			if owner, err := user.LookupId(name); err == nil {
+				name = owner.Username
+			}
+			names[stat.Uid] = name
+		}
+		return []string{name}, nil
+	}
+}
+
+// Aggregate globs files and sums their count, apparent size and allocated size per group
+func (f *Finder) Aggregate(pattern string, grouping Grouping) (report *AggregateReport, err error) {
+	var globResult []file.FileInfoEx
+	if globResult, err = f.Glob(pattern); err != nil {
+		return
+	}
+	var base string
+	if base, err = filepath.Abs(globBase(pattern)); err != nil {
+		err = errors.Wrap(err, "filepath.Abs")
+		return
+	}
+	keys := make([][]string, len(globResult))
+	errs := make([]error, len(globResult))
+	runParallel(f.numCheckers, len(globResult), func(idx int) {
+		keys[idx], errs[idx] = grouping(base, globResult[idx])
+	})
+	report = &AggregateReport{}
+	byKey := map[string]*AggregateEntry{}
+	// hard linked inodes already counted in given group, "" is used for total
+	linked := map[[2]uint64]map[string]bool{}
+	for idx, info := range globResult {
+		if errs[idx] != nil {
+			err = errors.Wrap(errs[idx], "grouping")
+			return
+		}
+		var allocated int64
+		var counted map[string]bool
+		if stat, ok := file.StatOf(info); ok {
+			allocated = stat.Allocated()
+			if stat.Nlink > 1 {
+				inode := [2]uint64{stat.Dev, stat.Ino}
+				if counted = linked[inode]; counted == nil {
+					counted = map[string]bool{}
+					linked[inode] = counted
+				}
+			}
+		}
+		// allocatedOnce returns allocated size unless inode was already counted in group
+		allocatedOnce := func(group string) int64 {
+			if counted == nil {
+				return allocated
+			}
+			if counted[group] {
+				return 0
+			}
+			counted[group] = true
+			return allocated
+		}
+		report.Total.add(info.Size(), allocatedOnce(""))
+		for keyIdx, key := range keys[idx] {
+			entry := byKey[key]
+			if entry == nil {
+				entry = &AggregateEntry{Key: key}
+				if keyIdx > 0 {
+					entry.Parent = keys[idx][keyIdx-1]
+				}
+				byKey[key] = entry
+			}
+			entry.add(info.Size(), allocatedOnce("/"+key))
+		}
+	}
+	for _, entry := range byKey {
+		report.Entries = append(report.Entries, *entry)
+	}
+	report.Sort(SortByKey, false)
+	return
+}
+
+func (e *AggregateEntry) add(size, allocated int64) {
+	e.Count++
+	e.Size += size
+	e.Allocated += allocated
+}
+
+// globBase returns directory part of pattern that precedes first glob meta character
+func globBase(pattern string) string {
+	if idx := strings.IndexAny(pattern, "*?[{"); idx >= 0 {
+		pattern = pattern[:idx]
+		if strings.HasSuffix(pattern, "/") || strings.HasSuffix(pattern, string(filepath.Separator)) {
+			return filepath.Clean(pattern)
+		}
+	}
+	return filepath.Dir(pattern)
+}
+
+// Sort sorts entries by field. Ties are resolved by key.
+func (r *AggregateReport) Sort(field AggregateSortField, descending bool) {
+	value := func(e AggregateEntry) int64 {
+		switch field {
+		case SortByCount:
+			return int64(e.Count)
+		case SortBySize:
+			return e.Size
+		case SortByAllocated:
+			return e.Allocated
+		}
+		return 0
+	}
+	sort.SliceStable(r.Entries, func(i, j int) bool {
+		a, b := r.Entries[i], r.Entries[j]
+		if va, vb := value(a), value(b); va != vb {
+			return (va < vb) != descending
+		}
+		return (a.Key < b.Key) != (descending && field == SortByKey)
+	})
+}
+
+// Children returns entries which parent is given key. Useful for walking ByDirectory report as tree, root key is ".".
+func (r *AggregateReport) Children(key string) (children []AggregateEntry) {
+	for _, entry := range r.Entries {
+		if entry.Parent == key && entry.Key != key {
+			children = append(children, entry)
+		}
+	}
+	return
+}
+
+// WriteTable writes entries and total as aligned text table
+func (r *AggregateReport) WriteTable(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "KEY\tCOUNT\tSIZE\tALLOCATED")
+	for _, entry := range r.Entries {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\n", entry.Key, entry.Count, entry.Size, entry.Allocated)
+	}
+	fmt.Fprintf(table, "total\t%d\t%d\t%d\n", r.Total.Count, r.Total.Size, r.Total.Allocated)
+	return table.Flush()
+}
@@ -0,0 +1,99 @@
+package finder
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAggregateMockGlob() FileInfoExGlobFunc {
+	return newMockGlobFunc([]file.FileInfoEx{
+		&mockFileInfoEx{name: "root.TXT", abs: "/data/root.TXT", size: 10, mime: "text/plain", stat: &file.Stat{Blocks: 8, Uid: 4000000001}},
+		&mockFileInfoEx{name: "a.jpg", abs: "/data/a/a.jpg", size: 100, mime: "image/jpeg", stat: &file.Stat{Blocks: 1, Uid: 4000000001}},
+		&mockFileInfoEx{name: "b.txt", abs: "/data/a/b/b.txt", size: 1000, mime: "text/plain; charset=utf-8", stat: &file.Stat{Blocks: 2, Uid: 4000000002}},
+		&mockFileInfoEx{name: "c", abs: "/data/c/c", size: 5, mime: ""},
+	})
+}
+
+func TestFinder_Aggregate(t *testing.T) {
+	t.Run("ByDirectory", func(t *testing.T) {
+		report, err := New().SetGlobFunc(newAggregateMockGlob()).Aggregate("/data/**", ByDirectory(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []AggregateEntry{
+			{Key: ".", Count: 4, Size: 1115, Allocated: 5632},
+			{Key: "a", Parent: ".", Count: 2, Size: 1100, Allocated: 1536},
+			{Key: "c", Parent: ".", Count: 1, Size: 5},
+		}, report.Entries)
+		assert.Equal(t, AggregateEntry{Count: 4, Size: 1115, Allocated: 5632}, report.Total)
+		assert.Len(t, report.Children("."), 2)
+	})
+	t.Run("ByDirectoryDeep", func(t *testing.T) {
+		report, _ := New().SetGlobFunc(newAggregateMockGlob()).Aggregate("/data/a/**/*", ByDirectory(5))
+		assert.Equal(t, []AggregateEntry{
+			{Key: ".", Count: 4, Size: 1115, Allocated: 5632},
+			{Key: "b", Parent: ".", Count: 1, Size: 1000, Allocated: 1024},
+		}, report.Entries)
+	})
+	t.Run("HardLinks", func(t *testing.T) {
+		linked := &file.Stat{Dev: 1, Ino: 2, Nlink: 2, Blocks: 8}
+		report, _ := New().SetGlobFunc(newMockGlobFunc([]file.FileInfoEx{
+			&mockFileInfoEx{name: "x", abs: "/data/a/x", size: 10, stat: linked},
+			&mockFileInfoEx{name: "y", abs: "/data/b/y", size: 10, stat: linked},
+		})).Aggregate("/data/**", ByDirectory(1))
+		assert.Equal(t, []AggregateEntry{
+			{Key: ".", Count: 2, Size: 20, Allocated: 4096},
+			{Key: "a", Parent: ".", Count: 1, Size: 10, Allocated: 4096},
+			{Key: "b", Parent: ".", Count: 1, Size: 10, Allocated: 4096},
+		}, report.Entries)
+		assert.Equal(t, AggregateEntry{Count: 2, Size: 20, Allocated: 4096}, report.Total)
+	})
+	t.Run("ByExtension", func(t *testing.T) {
+		report, _ := New().SetGlobFunc(newAggregateMockGlob()).Aggregate("/data/**", ByExtension())
+		report.Sort(SortBySize, true)
+		assert.Equal(t, []AggregateEntry{
+			{Key: ".txt", Count: 2, Size: 1010, Allocated: 5120},
+			{Key: ".jpg", Count: 1, Size: 100, Allocated: 512},
+			{Key: "", Count: 1, Size: 5},
+		}, report.Entries)
+	})
+	t.Run("ByMime", func(t *testing.T) {
+		report, _ := New().SetGlobFunc(newAggregateMockGlob()).Aggregate("/data/**", ByMime())
+		report.Sort(SortByCount, true)
+		assert.Equal(t, "text/plain", report.Entries[0].Key)
+		assert.Equal(t, 2, report.Entries[0].Count)
+	})
+	t.Run("ByOwner", func(t *testing.T) {
+		report, _ := New().SetGlobFunc(newAggregateMockGlob()).Aggregate("/data/**", ByOwner())
+		report.Sort(SortByAllocated, false)
+		assert.Equal(t, []AggregateEntry{
+			{Key: "", Count: 1, Size: 5},
+			{Key: "4000000002", Count: 1, Size: 1000, Allocated: 1024},
+			{Key: "4000000001", Count: 2, Size: 110, Allocated: 4608},
+		}, report.Entries)
+	})
+	t.Run("WriteTable", func(t *testing.T) {
+		report, _ := New().SetGlobFunc(newAggregateMockGlob()).Aggregate("/data/**", ByDirectory(0))
+		buf := &bytes.Buffer{}
+		assert.NoError(t, report.WriteTable(buf))
+		assert.Equal(t, "KEY    COUNT  SIZE  ALLOCATED\n"+
+			".      4      1115  5632\n"+
+			"total  4      1115  5632\n", buf.String())
+	})
+}
+
+func TestGlobBase(t *testing.T) {
+	testExpectations := map[string]string{
+		"/data/**":        "/data",
+		"/data/a*/b":      "/data",
+		"data/file.txt":   "data",
+		"*.go":            ".",
+		"./test_files/**": "test_files",
+	}
+	for pattern, expected := range testExpectations {
+		assert.Equal(t, expected, globBase(pattern), pattern)
+	}
+}
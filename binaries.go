@@ -0,0 +1,130 @@
+package finder
+
+import (
+	"regexp"
+
+	"github.com/duffpl/go-finder/binmeta"
+	"github.com/duffpl/go-finder/file"
+)
+
+const binaryInfoAttr = "binary-info"
+
+// BinaryInfo returns format, architecture, linkage and Go build info of ELF, PE or Mach-O binary. Only headers,
+// section table and Go build info blob are read.
+func BinaryInfo(fiex file.FileInfoEx) (info *binmeta.Info, err error) {
+	var v interface{}
+	if v, err = file.Attr(fiex, binaryInfoAttr, func(path string) (interface{}, error) {
+		return binmeta.ByPath(path)
+	}); err != nil {
+		return
+	}
+	info = v.(*binmeta.Info)
+	return
+}
+
+func (f *Finder) addBinaryInfoFilter(name string, cb func(info *binmeta.Info) bool) {
+	f.addInfoFilter(name, CostHead, func(fiex file.FileInfoEx) (interface{}, error) {
+		return BinaryInfo(fiex)
+	}, func(info interface{}) bool {
+		return cb(info.(*binmeta.Info))
+	})
+}
+
+// BinaryFormat adds matching of executables in any of given formats
+func (f *Finder) BinaryFormat(formats ...binmeta.Format) *Finder {
+	if f.lastErr != nil { return f }
+	f.addBinaryInfoFilter("BinaryFormat", func(info *binmeta.Info) bool {
+		for _, format := range formats {
+			if info.Format == format {
+				return true
+			}
+		}
+		return false
+	})
+	return f
+}
+
+// BinaryArch adds matching of executables built for any of given architectures (GOARCH names, e.g. "arm64").
+// Universal Mach-O binaries match if they contain any of them.
+func (f *Finder) BinaryArch(archs ...string) *Finder {
+	if f.lastErr != nil { return f }
+	f.addBinaryInfoFilter("BinaryArch", func(info *binmeta.Info) bool {
+		for _, binaryArch := range info.Archs {
+			for _, arch := range archs {
+				if binaryArch == arch {
+					return true
+				}
+			}
+		}
+		return false
+	})
+	return f
+}
+
+// BinaryType adds matching of binaries of any of given types
+func (f *Finder) BinaryType(types ...binmeta.Type) *Finder {
+	if f.lastErr != nil { return f }
+	f.addBinaryInfoFilter("BinaryType", func(info *binmeta.Info) bool {
+		for _, binaryType := range types {
+			if info.Type == binaryType {
+				return true
+			}
+		}
+		return false
+	})
+	return f
+}
+
+// StaticallyLinked adds matching of statically (static = true) or dynamically (static = false) linked binaries
+func (f *Finder) StaticallyLinked(static bool) *Finder {
+	if f.lastErr != nil { return f }
+	f.addBinaryInfoFilter("StaticallyLinked", func(info *binmeta.Info) bool {
+		return info.Static == static
+	})
+	return f
+}
+
+// Stripped adds matching of binaries without (stripped = true) or with (stripped = false) symbol table
+func (f *Finder) Stripped(stripped bool) *Finder {
+	if f.lastErr != nil { return f }
+	f.addBinaryInfoFilter("Stripped", func(info *binmeta.Info) bool {
+		return info.Stripped == stripped
+	})
+	return f
+}
+
+// GoBinary adds matching of binaries with embedded Go build info
+func (f *Finder) GoBinary() *Finder {
+	if f.lastErr != nil { return f }
+	f.addBinaryInfoFilter("GoBinary", func(info *binmeta.Info) bool {
+		return info.Go != nil
+	})
+	return f
+}
+
+// GoVersion adds matching against version of Go toolchain that built binary using regexp pattern, e.g.
+// GoVersion(`^go1\.21\.`)
+func (f *Finder) GoVersion(pattern string) *Finder {
+	if f.lastErr != nil { return f }
+	var compiled *regexp.Regexp
+	if compiled, f.lastErr = regexp.Compile(pattern); f.lastErr != nil {
+		return f
+	}
+	f.addBinaryInfoFilter("GoVersion", func(info *binmeta.Info) bool {
+		return info.Go != nil && compiled.MatchString(info.Go.GoVersion)
+	})
+	return f
+}
+
+// GoModule adds matching against main module path of Go binary using regexp pattern
+func (f *Finder) GoModule(pattern string) *Finder {
+	if f.lastErr != nil { return f }
+	var compiled *regexp.Regexp
+	if compiled, f.lastErr = regexp.Compile(pattern); f.lastErr != nil {
+		return f
+	}
+	f.addBinaryInfoFilter("GoModule", func(info *binmeta.Info) bool {
+		return info.Go != nil && compiled.MatchString(info.Go.Main.Path)
+	})
+	return f
+}
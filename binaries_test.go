@@ -0,0 +1,54 @@
+package finder
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/duffpl/go-finder/binmeta"
+	"github.com/duffpl/go-finder/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinder_BinaryFilters(t *testing.T) {
+	mockGlob := newMockGlobFunc([]file.FileInfoEx{
+		withAttr("linux-arm64", binaryInfoAttr, &binmeta.Info{
+			Format: binmeta.ELF, Type: binmeta.Executable, Arch: "arm64", Archs: []string{"arm64"}, Static: true,
+		}),
+		withAttr("windows-dll", binaryInfoAttr, &binmeta.Info{
+			Format: binmeta.PE, Type: binmeta.SharedLibrary, Arch: "amd64", Archs: []string{"amd64"}, Stripped: true,
+		}),
+		withAttr("darwin-universal", binaryInfoAttr, &binmeta.Info{
+			Format: binmeta.MachO, Type: binmeta.Executable, Arch: "amd64", Archs: []string{"amd64", "arm64"},
+		}),
+	})
+	runFilterExpectations(t, mockGlob, "test-glob", []filterExpectation{
+		{"BinaryFormat", New().BinaryFormat(binmeta.ELF, binmeta.PE), []string{"linux-arm64", "windows-dll"}},
+		{"BinaryArch", New().BinaryArch("arm64"), []string{"darwin-universal", "linux-arm64"}},
+		{"BinaryType", New().BinaryType(binmeta.SharedLibrary), []string{"windows-dll"}},
+		{"StaticallyLinked", New().StaticallyLinked(true), []string{"linux-arm64"}},
+		{"DynamicallyLinked", New().StaticallyLinked(false), []string{"darwin-universal", "windows-dll"}},
+		{"Stripped", New().Stripped(true), []string{"windows-dll"}},
+		{"GoBinary", New().GoBinary(), nil},
+	})
+}
+
+func TestFinder_GoBinary_integration(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	result, err := New().
+		BinaryArch(runtime.GOARCH).
+		GoBinary().
+		GoVersion("^" + runtime.Version() + "$").
+		Glob(path)
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	result, err = New().GoModule("^no/such/module$").Glob(path)
+	assert.NoError(t, err)
+	assert.Len(t, result, 0)
+	result, err = New().GoBinary().Glob("./test_files/mime/*")
+	assert.NoError(t, err)
+	assert.Len(t, result, 0)
+}
@@ -0,0 +1,270 @@
+package binmeta
+
+import (
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Format is executable container format
+type Format string
+
+const (
+	ELF   Format = "elf"
+	PE    Format = "pe"
+	MachO Format = "macho"
+)
+
+// Type is kind of binary
+type Type string
+
+const (
+	Executable    Type = "executable"
+	SharedLibrary Type = "shared-library"
+	Object        Type = "object"
+	Core          Type = "core"
+	UnknownType   Type = "unknown"
+)
+
+// Info describes executable. Architectures use GOARCH names where such exist (amd64, arm64, 386, arm, ...).
+type Info struct {
+	Format Format
+	Type   Type
+	// Arch is architecture of binary. For universal Mach-O binaries it's first of Archs
+	Arch  string
+	Archs []string
+	// Static is true if binary doesn't use dynamic loader nor imports shared libraries
+	Static bool
+	// Stripped is true if binary has no symbol table
+	Stripped bool
+	// Go holds build info embedded by Go toolchain, nil for binaries not built with Go
+	Go *buildinfo.BuildInfo
+}
+
+var ErrUnknownFormat = errors.New("unknown binary format")
+
+// ByPath reads binary info from file
+func ByPath(path string) (result *Info, err error) {
+	var handle *os.File
+	if handle, err = os.Open(path); err != nil {
+		err = errors.Wrap(err, "os.Open")
+		return
+	}
+	defer handle.Close()
+	return Read(handle)
+}
+
+// Read reads binary info from r. Format is recognized by magic number.
+func Read(r io.ReaderAt) (result *Info, err error) {
+	magic := make([]byte, 4)
+	if _, err = r.ReadAt(magic, 0); err != nil {
+		if err == io.EOF {
+			err = ErrUnknownFormat
+		} else {
+			err = errors.Wrap(err, "read")
+		}
+		return
+	}
+	switch {
+	case string(magic) == elf.ELFMAG:
+		result, err = readELF(r)
+	case string(magic[:2]) == "MZ":
+		result, err = readPE(r)
+	case isMachO(magic):
+		result, err = readMachO(r)
+	default:
+		err = ErrUnknownFormat
+	}
+	if err != nil {
+		return
+	}
+	// build info is optional, binaries not built with Go return error here
+	if goInfo, goErr := buildinfo.Read(r); goErr == nil {
+		result.Go = goInfo
+	}
+	return
+}
+
+func readELF(r io.ReaderAt) (result *Info, err error) {
+	var f *elf.File
+	if f, err = elf.NewFile(r); err != nil {
+		err = errors.Wrap(err, "elf")
+		return
+	}
+	result = &Info{Format: ELF, Arch: elfArch(f)}
+	result.Archs = []string{result.Arch}
+	hasInterp := false
+	for _, prog := range f.Progs {
+		if prog.Type == elf.PT_INTERP {
+			hasInterp = true
+		}
+	}
+	libraries, _ := f.ImportedLibraries()
+	result.Static = !hasInterp && len(libraries) == 0
+	result.Stripped = f.Section(".symtab") == nil
+	switch f.Type {
+	case elf.ET_EXEC:
+		result.Type = Executable
+	case elf.ET_DYN:
+		// position independent executables are ET_DYN too, unlike libraries they have entry point
+		if hasInterp || (f.Entry != 0 && len(libraries) == 0) {
+			result.Type = Executable
+		} else {
+			result.Type = SharedLibrary
+		}
+	case elf.ET_REL:
+		result.Type = Object
+	case elf.ET_CORE:
+		result.Type = Core
+	default:
+		result.Type = UnknownType
+	}
+	return
+}
+
+func elfArch(f *elf.File) string {
+	switch f.Machine {
+	case elf.EM_X86_64:
+		return "amd64"
+	case elf.EM_386:
+		return "386"
+	case elf.EM_AARCH64:
+		return "arm64"
+	case elf.EM_ARM:
+		return "arm"
+	case elf.EM_RISCV:
+		if f.Class == elf.ELFCLASS64 {
+			return "riscv64"
+		}
+		return "riscv"
+	case elf.EM_PPC64:
+		if f.Data == elf.ELFDATA2LSB {
+			return "ppc64le"
+		}
+		return "ppc64"
+	case elf.EM_S390:
+		return "s390x"
+	case elf.EM_MIPS:
+		little := f.Data == elf.ELFDATA2LSB
+		switch {
+		case f.Class == elf.ELFCLASS64 && little:
+			return "mips64le"
+		case f.Class == elf.ELFCLASS64:
+			return "mips64"
+		case little:
+			return "mipsle"
+		}
+		return "mips"
+	case elf.EM_LOONGARCH:
+		return "loong64"
+	}
+	return f.Machine.String()
+}
+
+func readPE(r io.ReaderAt) (result *Info, err error) {
+	var f *pe.File
+	if f, err = pe.NewFile(r); err != nil {
+		err = errors.Wrap(err, "pe")
+		return
+	}
+	result = &Info{Format: PE, Arch: peArch(f.Machine)}
+	result.Archs = []string{result.Arch}
+	libraries, _ := f.ImportedLibraries()
+	result.Static = len(libraries) == 0
+	result.Stripped = f.NumberOfSymbols == 0
+	switch {
+	case f.Characteristics&pe.IMAGE_FILE_DLL != 0:
+		result.Type = SharedLibrary
+	case f.Characteristics&pe.IMAGE_FILE_EXECUTABLE_IMAGE != 0:
+		result.Type = Executable
+	default:
+		result.Type = Object
+	}
+	return
+}
+
+func peArch(machine uint16) string {
+	switch machine {
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		return "amd64"
+	case pe.IMAGE_FILE_MACHINE_I386:
+		return "386"
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		return "arm64"
+	case pe.IMAGE_FILE_MACHINE_ARMNT, pe.IMAGE_FILE_MACHINE_ARM:
+		return "arm"
+	}
+	return "unknown"
+}
+
+func isMachO(magic []byte) bool {
+	switch uint32(magic[0])<<24 | uint32(magic[1])<<16 | uint32(magic[2])<<8 | uint32(magic[3]) {
+	case macho.Magic32, macho.Magic64, macho.MagicFat, 0xcefaedfe, 0xcffaedfe:
+		return true
+	}
+	return false
+}
+
+func readMachO(r io.ReaderAt) (result *Info, err error) {
+	var files []*macho.File
+	if fat, fatErr := macho.NewFatFile(r); fatErr == nil {
+		for _, arch := range fat.Arches {
+			files = append(files, arch.File)
+		}
+	} else {
+		var f *macho.File
+		if f, err = macho.NewFile(r); err != nil {
+			err = errors.Wrap(err, "macho")
+			return
+		}
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, ErrUnknownFormat
+	}
+	result = &Info{Format: MachO, Static: true, Stripped: true}
+	for _, f := range files {
+		result.Archs = append(result.Archs, machoArch(f.Cpu))
+		if libraries, _ := f.ImportedLibraries(); len(libraries) > 0 {
+			result.Static = false
+		}
+		if f.Symtab != nil && len(f.Symtab.Syms) > 0 {
+			result.Stripped = false
+		}
+	}
+	result.Arch = result.Archs[0]
+	switch files[0].Type {
+	case macho.TypeExec:
+		result.Type = Executable
+	case macho.TypeDylib, macho.TypeBundle:
+		result.Type = SharedLibrary
+	case macho.TypeObj:
+		result.Type = Object
+	default:
+		result.Type = UnknownType
+	}
+	return
+}
+
+func machoArch(cpu macho.Cpu) string {
+	switch cpu {
+	case macho.CpuAmd64:
+		return "amd64"
+	case macho.Cpu386:
+		return "386"
+	case macho.CpuArm64:
+		return "arm64"
+	case macho.CpuArm:
+		return "arm"
+	case macho.CpuPpc64:
+		return "ppc64"
+	case macho.CpuPpc:
+		return "ppc"
+	}
+	return "unknown"
+}
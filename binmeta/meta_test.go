@@ -0,0 +1,76 @@
+package binmeta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// minimalELF builds 64-bit little endian ELF header without sections and program headers
+func minimalELF(fileType uint16, machine uint16, entry uint64) []byte {
+	header := make([]byte, 64)
+	copy(header, "\x7fELF\x02\x01\x01")
+	binary.LittleEndian.PutUint16(header[16:], fileType)
+	binary.LittleEndian.PutUint16(header[18:], machine)
+	binary.LittleEndian.PutUint32(header[20:], 1)
+	binary.LittleEndian.PutUint64(header[24:], entry)
+	binary.LittleEndian.PutUint16(header[52:], 64)
+	binary.LittleEndian.PutUint16(header[54:], 56)
+	binary.LittleEndian.PutUint16(header[58:], 64)
+	return header
+}
+
+func TestRead(t *testing.T) {
+	testExpectations := []struct {
+		name string
+		data []byte
+		info *Info
+	}{
+		{"StaticArm64Executable", minimalELF(2, 183, 0x400000), &Info{
+			Format: ELF, Type: Executable, Arch: "arm64", Archs: []string{"arm64"}, Static: true, Stripped: true,
+		}},
+		{"StaticPie", minimalELF(3, 62, 0x1000), &Info{
+			Format: ELF, Type: Executable, Arch: "amd64", Archs: []string{"amd64"}, Static: true, Stripped: true,
+		}},
+		{"Object", minimalELF(1, 62, 0), &Info{
+			Format: ELF, Type: Object, Arch: "amd64", Archs: []string{"amd64"}, Static: true, Stripped: true,
+		}},
+	}
+	for _, expectation := range testExpectations {
+		t.Run(expectation.name, func(t *testing.T) {
+			info, err := Read(bytes.NewReader(expectation.data))
+			assert.NoError(t, err)
+			assert.Equal(t, expectation.info, info)
+		})
+	}
+	t.Run("UnknownFormat", func(t *testing.T) {
+		_, err := Read(bytes.NewReader([]byte("plain text")))
+		assert.Equal(t, ErrUnknownFormat, err)
+		_, err = Read(bytes.NewReader(nil))
+		assert.Equal(t, ErrUnknownFormat, err)
+	})
+}
+
+func TestByPath_TestBinary(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	info, err := ByPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFormat := map[string]Format{"linux": ELF, "windows": PE, "darwin": MachO}[runtime.GOOS]
+	if expectedFormat != "" {
+		assert.Equal(t, expectedFormat, info.Format)
+	}
+	assert.Equal(t, Executable, info.Type)
+	assert.Equal(t, runtime.GOARCH, info.Arch)
+	if assert.NotNil(t, info.Go) {
+		assert.Equal(t, runtime.Version(), info.Go.GoVersion)
+	}
+}
@@ -0,0 +1,273 @@
+package checksum
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Context triggered piecewise hashing in the spirit of ssdeep/spamsum. Hash has form "blocksize:sig1:sig2" where sig1
+// is computed with given block size and sig2 with doubled one. Two hashes can be compared only if their block sizes
+// are equal or differ by factor of two.
+
+const (
+	fuzzyRollingWindow = 7
+	fuzzyMinBlockSize  = 3
+	fuzzySpamSumLength = 64
+	fuzzyHashPrime     = 0x01000193
+	fuzzyHashInit      = 0x28021967
+	fuzzyB64           = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+)
+
+type fuzzyRollingHash struct {
+	window     [fuzzyRollingWindow]uint32
+	h1, h2, h3 uint32
+	n          uint32
+}
+
+func (r *fuzzyRollingHash) roll(c byte) uint32 {
+	r.h2 -= r.h1
+	r.h2 += fuzzyRollingWindow * uint32(c)
+	r.h1 += uint32(c)
+	r.h1 -= r.window[r.n%fuzzyRollingWindow]
+	r.window[r.n%fuzzyRollingWindow] = uint32(c)
+	r.n++
+	r.h3 <<= 5
+	r.h3 ^= uint32(c)
+	return r.h1 + r.h2 + r.h3
+}
+
+// fuzzySignature holds signature chars. Last slot is overwritten once signature is full.
+type fuzzySignature struct {
+	chars   []byte
+	max     int
+	tailSet bool
+}
+
+func (s *fuzzySignature) push(c byte) (reset bool) {
+	if len(s.chars) < s.max-1 {
+		s.chars = append(s.chars, c)
+		return true
+	}
+	if s.tailSet {
+		s.chars[len(s.chars)-1] = c
+	} else {
+		s.chars = append(s.chars, c)
+		s.tailSet = true
+	}
+	return false
+}
+
+func (s *fuzzySignature) finish(c byte) {
+	if s.tailSet {
+		s.chars[len(s.chars)-1] = c
+	} else {
+		s.chars = append(s.chars, c)
+	}
+}
+
+// FuzzyByPath computes fuzzy hash of file under path
+func FuzzyByPath(path string) (hash string, err error) {
+	var handle *os.File
+	if handle, err = os.Open(path); err != nil {
+		err = errors.Wrap(err, "os.Open")
+		return
+	}
+	defer handle.Close()
+	var stat os.FileInfo
+	if stat, err = handle.Stat(); err != nil {
+		err = errors.Wrap(err, "stat")
+		return
+	}
+	return Fuzzy(handle, stat.Size())
+}
+
+// Fuzzy computes fuzzy hash of size bytes read from r. Reader is rewound and read again when initial block size
+// guess produces too short signature.
+func Fuzzy(r io.ReadSeeker, size int64) (hash string, err error) {
+	blockSize := uint32(fuzzyMinBlockSize)
+	for int64(blockSize)*fuzzySpamSumLength < size {
+		blockSize *= 2
+	}
+	for {
+		if _, err = r.Seek(0, io.SeekStart); err != nil {
+			err = errors.Wrap(err, "seek")
+			return
+		}
+		var sig1, sig2 []byte
+		if sig1, sig2, err = fuzzyPass(bufio.NewReader(r), blockSize); err != nil {
+			return
+		}
+		if blockSize > fuzzyMinBlockSize && len(sig1) < fuzzySpamSumLength/2 {
+			blockSize /= 2
+			continue
+		}
+		hash = fmt.Sprintf("%d:%s:%s", blockSize, sig1, sig2)
+		return
+	}
+}
+
+func fuzzyPass(r io.ByteReader, blockSize uint32) (sig1, sig2 []byte, err error) {
+	var (
+		roll       fuzzyRollingHash
+		h          uint32
+		c          byte
+		s1         = fuzzySignature{max: fuzzySpamSumLength}
+		s2         = fuzzySignature{max: fuzzySpamSumLength / 2}
+		sum1, sum2 uint32 = fuzzyHashInit, fuzzyHashInit
+	)
+	for {
+		if c, err = r.ReadByte(); err != nil {
+			if err == io.EOF {
+				err = nil
+				break
+			}
+			err = errors.Wrap(err, "read")
+			return
+		}
+		h = roll.roll(c)
+		sum1 = sum1*fuzzyHashPrime ^ uint32(c)
+		sum2 = sum2*fuzzyHashPrime ^ uint32(c)
+		if h%blockSize == blockSize-1 {
+			if s1.push(fuzzyB64[sum1%64]) {
+				sum1 = fuzzyHashInit
+			}
+		}
+		if h%(blockSize*2) == blockSize*2-1 {
+			if s2.push(fuzzyB64[sum2%64]) {
+				sum2 = fuzzyHashInit
+			}
+		}
+	}
+	if h != 0 {
+		s1.finish(fuzzyB64[sum1%64])
+		s2.finish(fuzzyB64[sum2%64])
+	}
+	return s1.chars, s2.chars, nil
+}
+
+type fuzzyParsed struct {
+	blockSize  int
+	sig1, sig2 string
+}
+
+func parseFuzzy(hash string) (result fuzzyParsed, err error) {
+	parts := strings.SplitN(hash, ":", 3)
+	if len(parts) != 3 {
+		err = errors.New("malformed fuzzy hash: " + hash)
+		return
+	}
+	if result.blockSize, err = strconv.Atoi(parts[0]); err != nil || result.blockSize <= 0 {
+		err = errors.New("malformed fuzzy hash block size: " + hash)
+		return
+	}
+	result.sig1 = fuzzyEliminateSequences(parts[1])
+	result.sig2 = fuzzyEliminateSequences(parts[2])
+	return
+}
+
+// FuzzyCompare returns similarity score of two fuzzy hashes in range 0 (no similarity) to 100 (identical)
+func FuzzyCompare(a, b string) (score int, err error) {
+	var pa, pb fuzzyParsed
+	if pa, err = parseFuzzy(a); err != nil {
+		return
+	}
+	if pb, err = parseFuzzy(b); err != nil {
+		return
+	}
+	switch {
+	case pa.blockSize == pb.blockSize:
+		if pa.sig1 == pb.sig1 && pa.sig2 == pb.sig2 {
+			return 100, nil
+		}
+		score = fuzzyScore(pa.sig1, pb.sig1, pa.blockSize)
+		if score2 := fuzzyScore(pa.sig2, pb.sig2, pa.blockSize*2); score2 > score {
+			score = score2
+		}
+	case pa.blockSize == pb.blockSize*2:
+		score = fuzzyScore(pa.sig1, pb.sig2, pa.blockSize)
+	case pb.blockSize == pa.blockSize*2:
+		score = fuzzyScore(pa.sig2, pb.sig1, pb.blockSize)
+	}
+	return
+}
+
+// fuzzyEliminateSequences shortens runs of identical chars to 3 as they carry little information
+func fuzzyEliminateSequences(sig string) string {
+	result := make([]byte, 0, len(sig))
+	for i := 0; i < len(sig); i++ {
+		if i >= 3 && sig[i] == sig[i-1] && sig[i] == sig[i-2] && sig[i] == sig[i-3] {
+			continue
+		}
+		result = append(result, sig[i])
+	}
+	return string(result)
+}
+
+func fuzzyScore(s1, s2 string, blockSize int) int {
+	if len(s1) < fuzzyRollingWindow || len(s2) < fuzzyRollingWindow || !fuzzyHasCommonSubstring(s1, s2) {
+		return 0
+	}
+	score := fuzzyEditDistance(s1, s2) * fuzzySpamSumLength / (len(s1) + len(s2))
+	score = 100 * score / fuzzySpamSumLength
+	if score >= 100 {
+		return 0
+	}
+	score = 100 - score
+	// small block sizes produce short signatures that could match by accident, so score is capped
+	if blockSize < (99+fuzzyRollingWindow)/fuzzyRollingWindow*fuzzyMinBlockSize {
+		shorter := len(s1)
+		if len(s2) < shorter {
+			shorter = len(s2)
+		}
+		if limit := blockSize / fuzzyMinBlockSize * shorter; score > limit {
+			score = limit
+		}
+	}
+	return score
+}
+
+func fuzzyHasCommonSubstring(s1, s2 string) bool {
+	substrings := make(map[string]struct{}, len(s1))
+	for i := 0; i+fuzzyRollingWindow <= len(s1); i++ {
+		substrings[s1[i:i+fuzzyRollingWindow]] = struct{}{}
+	}
+	for i := 0; i+fuzzyRollingWindow <= len(s2); i++ {
+		if _, found := substrings[s2[i:i+fuzzyRollingWindow]]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzyEditDistance is Levenshtein distance with substitution cost of 2
+func fuzzyEditDistance(s1, s2 string) int {
+	prev := make([]int, len(s2)+1)
+	curr := make([]int, len(s2)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s1); i++ {
+		curr[0] = i
+		for j := 1; j <= len(s2); j++ {
+			cost := prev[j-1]
+			if s1[i-1] != s2[j-1] {
+				cost += 2
+			}
+			if prev[j]+1 < cost {
+				cost = prev[j] + 1
+			}
+			if curr[j-1]+1 < cost {
+				cost = curr[j-1] + 1
+			}
+			curr[j] = cost
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(s2)]
+}
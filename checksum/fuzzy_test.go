@@ -0,0 +1,74 @@
+package checksum
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fuzzyTestData(seed int64, size int) []byte {
+	words := []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do"}
+	rnd := rand.New(rand.NewSource(seed))
+	buf := &bytes.Buffer{}
+	for buf.Len() < size {
+		buf.WriteString(words[rnd.Intn(len(words))])
+		buf.WriteByte(' ')
+	}
+	return buf.Bytes()
+}
+
+func fuzzyOf(t *testing.T, data []byte) string {
+	hash, err := Fuzzy(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+func TestFuzzy(t *testing.T) {
+	original := fuzzyTestData(1, 20000)
+	modified := append([]byte("a new header line\n"), original...)
+	copy(modified[10000:], "some changed bytes in the middle")
+	unrelated := fuzzyTestData(2, 20000)
+
+	originalHash := fuzzyOf(t, original)
+	assert.Regexp(t, `^\d+:[A-Za-z0-9+/]+:[A-Za-z0-9+/]+$`, originalHash)
+	testExpectations := []struct {
+		name     string
+		other    []byte
+		minScore int
+		maxScore int
+	}{
+		{"Identical", original, 100, 100},
+		{"Modified", modified, 50, 99},
+		{"Unrelated", unrelated, 0, 20},
+	}
+	for _, expectation := range testExpectations {
+		t.Run(expectation.name, func(t *testing.T) {
+			score, err := FuzzyCompare(originalHash, fuzzyOf(t, expectation.other))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.True(t, score >= expectation.minScore && score <= expectation.maxScore, "score: %d", score)
+		})
+	}
+	t.Run("IncompatibleBlockSizes", func(t *testing.T) {
+		score, err := FuzzyCompare("3:abcdefgh:abcd", "48:abcdefgh:abcd")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, score)
+	})
+	t.Run("Malformed", func(t *testing.T) {
+		_, err := FuzzyCompare("abc", originalHash)
+		assert.Error(t, err)
+	})
+}
+
+func TestFuzzyByPath(t *testing.T) {
+	hash, err := FuzzyByPath("../test_files/mime/text.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "3:", hash[:2])
+}
@@ -0,0 +1,106 @@
+package checksum
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Algorithm is string const enum for supported digest algorithms
+type Algorithm string
+
+const (
+	MD5    Algorithm = "md5"
+	SHA1   Algorithm = "sha1"
+	SHA256 Algorithm = "sha256"
+	SHA512 Algorithm = "sha512"
+)
+
+// ByPath computes digest of file under path using algorithm
+func (a Algorithm) ByPath(path string) (checksum []byte, err error) {
+	switch a {
+	case MD5:
+		return MD5ByPath(path)
+	case SHA1:
+		return SHA1ByPath(path)
+	case SHA256:
+		return SHA256ByPath(path)
+	case SHA512:
+		return SHA512ByPath(path)
+	}
+	err = fmt.Errorf("unsupported algorithm: %s", a)
+	return
+}
+
+// AlgorithmBySize guesses algorithm from digest length in bytes. This is how md5sum/sha*sum manifests are told apart
+// when the tool name isn't known.
+func AlgorithmBySize(size int) (a Algorithm, err error) {
+	switch size {
+	case md5.Size:
+		a = MD5
+	case sha1.Size:
+		a = SHA1
+	case sha256.Size:
+		a = SHA256
+	case sha512.Size:
+		a = SHA512
+	default:
+		err = fmt.Errorf("unknown digest length: %d", size)
+	}
+	return
+}
+
+func SHA1ByPath(path string) (checksum []byte, err error) {
+	return hashByPath(sha1.New(), path)
+}
+
+func SHA256ByPath(path string) (checksum []byte, err error) {
+	return hashByPath(sha256.New(), path)
+}
+
+func SHA512ByPath(path string) (checksum []byte, err error) {
+	return hashByPath(sha512.New(), path)
+}
+
+func hashByPath(checksumWriter hash.Hash, path string) (checksum []byte, err error) {
+	return hashByPathCounting(checksumWriter, path, nil)
+}
+
+func hashByPathCounting(checksumWriter hash.Hash, path string, counter func(n int64)) (checksum []byte, err error) {
+	var handle *os.File
+	if handle, err = os.Open(path); err != nil {
+		err = errors.Wrap(err, "os.Open")
+		return
+	}
+	defer handle.Close()
+	var reader io.Reader = handle
+	if counter != nil {
+		reader = &countingReader{handle, counter}
+	}
+	if _, err = io.Copy(checksumWriter, reader); err != nil {
+		err = errors.Wrap(err, "io.Copy")
+		return
+	}
+	checksum = checksumWriter.Sum(nil)
+	return
+}
+
+type countingReader struct {
+	io.Reader
+	counter func(n int64)
+}
+
+func (r *countingReader) Read(p []byte) (n int, err error) {
+	n, err = r.Reader.Read(p)
+	if n > 0 {
+		r.counter(int64(n))
+	}
+	return
+}
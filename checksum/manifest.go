@@ -0,0 +1,96 @@
+package checksum
+
+import (
+	"bufio"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ManifestEntry is single line of checksum manifest
+type ManifestEntry struct {
+	Path      string
+	Digest    []byte
+	Algorithm Algorithm
+}
+
+// Manifest is list of expected digests. Entry paths are relative to Base unless they are absolute.
+type Manifest struct {
+	Base    string
+	Entries []ManifestEntry
+}
+
+var (
+	gnuManifestLine = regexp.MustCompile(`^\\?([0-9a-fA-F]+) [ *](.+)$`)
+	bsdManifestLine = regexp.MustCompile(`^\\?(MD5|SHA1|SHA256|SHA512) ?\((.+)\) ?= ?([0-9a-fA-F]+)$`)
+)
+
+// ParseManifest reads manifest in md5sum/sha*sum format ("<hex>  <path>" or "<hex> *<path>"). BSD style lines
+// ("SHA256 (path) = <hex>") are accepted as well. Empty lines and lines starting with # are skipped. Algorithm of each
+// entry is detected from digest length.
+func ParseManifest(r io.Reader, base string) (result *Manifest, err error) {
+	result = &Manifest{Base: base}
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var entry ManifestEntry
+		if entry, err = parseManifestLine(line); err != nil {
+			err = errors.Wrapf(err, "line %d", lineNo)
+			return
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+	if err = scanner.Err(); err != nil {
+		err = errors.Wrap(err, "scan")
+	}
+	return
+}
+
+// LoadManifest parses manifest file. Entries are resolved relative to directory containing the manifest, which is how
+// md5sum -c treats them.
+func LoadManifest(path string) (result *Manifest, err error) {
+	var handle *os.File
+	if handle, err = os.Open(path); err != nil {
+		err = errors.Wrap(err, "os.Open")
+		return
+	}
+	defer handle.Close()
+	var base string
+	if base, err = filepath.Abs(filepath.Dir(path)); err != nil {
+		err = errors.Wrap(err, "filepath.Abs")
+		return
+	}
+	return ParseManifest(handle, base)
+}
+
+func parseManifestLine(line string) (entry ManifestEntry, err error) {
+	var hexDigest string
+	escaped := strings.HasPrefix(line, `\`)
+	if m := bsdManifestLine.FindStringSubmatch(line); m != nil {
+		hexDigest, entry.Path = m[3], m[2]
+	} else if m := gnuManifestLine.FindStringSubmatch(line); m != nil {
+		hexDigest, entry.Path = m[1], m[2]
+	} else {
+		err = errors.New("malformed manifest line")
+		return
+	}
+	if escaped {
+		entry.Path = strings.NewReplacer(`\\`, `\`, `\n`, "\n").Replace(entry.Path)
+	}
+	if entry.Digest, err = hex.DecodeString(hexDigest); err != nil {
+		err = errors.Wrap(err, "hex.DecodeString")
+		return
+	}
+	entry.Algorithm, err = AlgorithmBySize(len(entry.Digest))
+	return
+}
@@ -0,0 +1,56 @@
+package checksum
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseManifest(t *testing.T) {
+	input := strings.Join([]string{
+		"# comment",
+		"3b5d5c3712955042212316173ccf37be  text file",
+		"87428fc522803d31065e7bce3cf03fe475096631e5e07bbd7a0fde60c4cf25c7 *binary.dat",
+		"",
+		"SHA1 (bsd.txt) = da39a3ee5e6b4b0d3255bfef95601890afd80709",
+		`\3b5d5c3712955042212316173ccf37be  with\nnewline`,
+	}, "\n")
+	manifest, err := ParseManifest(strings.NewReader(input), "base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "base", manifest.Base)
+	expectations := []struct {
+		path      string
+		algorithm Algorithm
+		hex       string
+	}{
+		{"text file", MD5, "3b5d5c3712955042212316173ccf37be"},
+		{"binary.dat", SHA256, "87428fc522803d31065e7bce3cf03fe475096631e5e07bbd7a0fde60c4cf25c7"},
+		{"bsd.txt", SHA1, "da39a3ee5e6b4b0d3255bfef95601890afd80709"},
+		{"with\nnewline", MD5, "3b5d5c3712955042212316173ccf37be"},
+	}
+	if !assert.Len(t, manifest.Entries, len(expectations)) {
+		return
+	}
+	for i, expectation := range expectations {
+		entry := manifest.Entries[i]
+		assert.Equal(t, expectation.path, entry.Path)
+		assert.Equal(t, expectation.algorithm, entry.Algorithm)
+		assert.Equal(t, expectation.hex, hex.EncodeToString(entry.Digest))
+	}
+	t.Run("Malformed", func(t *testing.T) {
+		_, err := ParseManifest(strings.NewReader("abc  file"), "")
+		assert.Error(t, err)
+	})
+}
+
+func TestAlgorithm_ByPath(t *testing.T) {
+	actual, err := SHA256.ByPath("../test_files/checksum/60b725f10c9c85c70d97880dfe8191b3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "87428fc522803d31065e7bce3cf03fe475096631e5e07bbd7a0fde60c4cf25c7", hex.EncodeToString(actual))
+}
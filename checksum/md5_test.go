@@ -1,6 +1,7 @@
 package checksum
 
 import (
+	"os"
 	"testing"
 	"github.com/stretchr/testify/assert"
 	"encoding/hex"
@@ -17,3 +18,17 @@ func TestMD5ByPath(t *testing.T) {
 	}
 	assert.Equal(t, expected, actual)
 }
+
+func TestMD5CountingByPath(t *testing.T) {
+	var read int64
+	actual, err := MD5CountingByPath("../test_files/checksum/3b5d5c3712955042212316173ccf37be", func(n int64) {
+		read += n
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := MD5ByPath("../test_files/checksum/3b5d5c3712955042212316173ccf37be")
+	assert.Equal(t, expected, actual)
+	info, _ := os.Stat("../test_files/checksum/3b5d5c3712955042212316173ccf37be")
+	assert.Equal(t, info.Size(), read)
+}
@@ -0,0 +1,145 @@
+package checksum
+
+import (
+	"bufio"
+	"encoding/hex"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Set is collection of digests with O(1) membership check. Digests can be added together with size of file they were
+// computed from. If every digest has known size, Set can be used to reject files by size before hashing them. Set
+// records length of digests, so algorithm they were computed with can be told (see Algorithm).
+type Set struct {
+	digests    map[string]struct{}
+	sizes      map[int64]struct{}
+	unsized    int
+	digestSize int
+}
+
+// NewSet creates Set from hex encoded digests
+func NewSet(hexDigests ...string) (result *Set, err error) {
+	result = newSet()
+	for _, hexDigest := range hexDigests {
+		if err = result.Add(hexDigest, -1); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// NewSetWithSizes creates Set from map of hex encoded digests to file sizes. Negative size means that size is unknown.
+func NewSetWithSizes(digestSizes map[string]int64) (result *Set, err error) {
+	result = newSet()
+	for hexDigest, size := range digestSizes {
+		if err = result.Add(hexDigest, size); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// ReadSet creates Set from newline separated hex encoded digests. Each line can contain file size after whitespace.
+// Empty lines and lines starting with # are skipped.
+func ReadSet(r io.Reader) (result *Set, err error) {
+	result = newSet()
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		size := int64(-1)
+		if len(fields) > 1 {
+			if size, err = strconv.ParseInt(fields[1], 10, 64); err != nil {
+				err = errors.Wrapf(err, "line %d", lineNo)
+				return
+			}
+		}
+		if err = result.Add(fields[0], size); err != nil {
+			err = errors.Wrapf(err, "line %d", lineNo)
+			return
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		err = errors.Wrap(err, "scan")
+	}
+	return
+}
+
+func newSet() *Set {
+	return &Set{
+		digests: map[string]struct{}{},
+		sizes:   map[int64]struct{}{},
+	}
+}
+
+// Add adds hex encoded digest to set. Negative size means that size is unknown.
+func (s *Set) Add(hexDigest string, size int64) (err error) {
+	var digest []byte
+	if digest, err = hex.DecodeString(hexDigest); err != nil {
+		err = errors.Wrap(err, "hex.DecodeString")
+		return
+	}
+	if len(digest) == 0 {
+		err = errors.New("empty digest")
+		return
+	}
+	s.digests[string(digest)] = struct{}{}
+	if s.digestSize == 0 {
+		s.digestSize = len(digest)
+	} else if s.digestSize != len(digest) {
+		s.digestSize = -1
+	}
+	if size < 0 {
+		s.unsized++
+	} else {
+		s.sizes[size] = struct{}{}
+	}
+	return
+}
+
+// Contains checks if digest is in set
+func (s *Set) Contains(digest []byte) bool {
+	_, found := s.digests[string(digest)]
+	return found
+}
+
+// MayContainSize returns false only if it's certain that no file of given size can be in set
+func (s *Set) MayContainSize(size int64) bool {
+	if s.unsized > 0 {
+		return true
+	}
+	_, found := s.sizes[size]
+	return found
+}
+
+// HasSizes returns true if every added digest has known file size
+func (s *Set) HasSizes() bool {
+	return s.unsized == 0 && len(s.sizes) > 0
+}
+
+// Len returns number of distinct digests
+func (s *Set) Len() int {
+	return len(s.digests)
+}
+
+// DigestSize returns length of digests in bytes. It's 0 for empty set and -1 if digests of different lengths were
+// added.
+func (s *Set) DigestSize() int {
+	return s.digestSize
+}
+
+// Algorithm guesses algorithm digests were computed with from their length
+func (s *Set) Algorithm() (a Algorithm, err error) {
+	if s.digestSize < 0 {
+		err = errors.New("digests of different lengths")
+		return
+	}
+	return AlgorithmBySize(s.digestSize)
+}
@@ -0,0 +1,57 @@
+package checksum
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet(t *testing.T) {
+	t.Run("NewSet", func(t *testing.T) {
+		set, err := NewSet("01", "0A0B")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, set.Contains([]byte{1}))
+		assert.True(t, set.Contains([]byte{10, 11}))
+		assert.False(t, set.Contains([]byte{2}))
+		assert.False(t, set.HasSizes())
+		assert.True(t, set.MayContainSize(123))
+		assert.Equal(t, 2, set.Len())
+		assert.Equal(t, -1, set.DigestSize())
+		_, err = set.Algorithm()
+		assert.Error(t, err)
+	})
+	t.Run("Algorithm", func(t *testing.T) {
+		set, _ := NewSet("da39a3ee5e6b4b0d3255bfef95601890afd80709")
+		algorithm, err := set.Algorithm()
+		assert.NoError(t, err)
+		assert.Equal(t, SHA1, algorithm)
+		assert.Equal(t, 20, set.DigestSize())
+	})
+	t.Run("NewSetWithSizes", func(t *testing.T) {
+		set, err := NewSetWithSizes(map[string]int64{"01": 100, "02": 200})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, set.HasSizes())
+		assert.True(t, set.MayContainSize(200))
+		assert.False(t, set.MayContainSize(300))
+	})
+	t.Run("ReadSet", func(t *testing.T) {
+		set, err := ReadSet(strings.NewReader("# known bad\n01 100\n\n02\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 2, set.Len())
+		assert.False(t, set.HasSizes())
+		assert.True(t, set.Contains([]byte{2}))
+	})
+	t.Run("InvalidDigest", func(t *testing.T) {
+		_, err := NewSet("xyz")
+		assert.Error(t, err)
+		_, err = ReadSet(strings.NewReader("01 not-a-size"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,65 @@
+package finder
+
+import (
+	"github.com/duffpl/go-finder/file"
+	"github.com/pkg/errors"
+)
+
+const holesAttr = "holes"
+
+// DiskUsage adds matching against number of bytes allocated on disk (see file.Stat.Allocated). Valid operators are
+// available in CmpOperator const. Files without stat data (non Linux platforms) aren't matched.
+func (f *Finder) DiskUsage(cmpOp CmpOperator, bytes int64) *Finder {
+	if f.lastErr != nil { return f }
+	if !isCmpOperatorValid(cmpOp) {
+		f.lastErr = Errors.InvalidCmpOperator
+		return f
+	}
+	f.addFilter("DiskUsage", CostMetadata, func(info file.FileInfoEx) (bool, error) {
+		stat, ok := file.StatOf(info)
+		return ok && compareInt64(cmpOp, stat.Allocated(), bytes), nil
+	})
+	return f
+}
+
+// AllocationRatio adds matching against ratio of allocated to apparent size. Ratio well below 1 indicates sparse
+// file, above 1 preallocated one. Empty files and files without stat data aren't matched.
+func (f *Finder) AllocationRatio(cmpOp CmpOperator, ratio float64) *Finder {
+	if f.lastErr != nil { return f }
+	if !isCmpOperatorValid(cmpOp) {
+		f.lastErr = Errors.InvalidCmpOperator
+		return f
+	}
+	f.addFilter("AllocationRatio", CostMetadata, func(info file.FileInfoEx) (bool, error) {
+		stat, ok := file.StatOf(info)
+		if !ok || info.Size() == 0 {
+			return false, nil
+		}
+		return compareFloat64(cmpOp, float64(stat.Allocated())/float64(info.Size()), ratio), nil
+	})
+	return f
+}
+
+// Sparse adds matching of files that occupy less space on disk than their length, judged by stat data only. Note that
+// files on compressing filesystems match as well, HasHoles gives exact answer on Linux.
+func (f *Finder) Sparse() *Finder {
+	return f.AllocationRatio(LessThan, 1)
+}
+
+// HasHoles adds matching of files with holes found with SEEK_DATA/SEEK_HOLE probing. Every file is opened, so it's
+// checked after stat based filters. Available only on Linux, elsewhere nothing is matched.
+func (f *Finder) HasHoles() *Finder {
+	if f.lastErr != nil { return f }
+	f.addFilter("HasHoles", CostHead, func(fiex file.FileInfoEx) (result bool, err error) {
+		var holes interface{}
+		if holes, err = file.Attr(fiex, holesAttr, func(path string) (interface{}, error) {
+			return file.HasHoles(path)
+		}); err != nil {
+			err = errors.Wrap(err, "HasHoles")
+			return
+		}
+		result = holes.(bool)
+		return
+	})
+	return f
+}
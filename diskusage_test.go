@@ -0,0 +1,51 @@
+package finder
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinder_DiskUsageFilters(t *testing.T) {
+	mockGlob := newMockGlobFunc([]file.FileInfoEx{
+		&mockFileInfoEx{name: "sparse", size: 1 << 20, stat: &file.Stat{Blocks: 8}},
+		&mockFileInfoEx{name: "dense", size: 4096, stat: &file.Stat{Blocks: 8}},
+		&mockFileInfoEx{name: "preallocated", size: 100, stat: &file.Stat{Blocks: 2048}},
+		&mockFileInfoEx{name: "empty", stat: &file.Stat{}},
+		&mockFileInfoEx{name: "no-stat", size: 100},
+	})
+	runFilterExpectations(t, mockGlob, "test-glob", []filterExpectation{
+		{"DiskUsage", New().DiskUsage(MoreOrEqual, 4096), []string{"dense", "preallocated", "sparse"}},
+		{"DiskUsageSmall", New().DiskUsage(LessThan, 4096), []string{"empty"}},
+		{"Sparse", New().Sparse(), []string{"sparse"}},
+		{"Preallocated", New().AllocationRatio(MoreThan, 10), []string{"preallocated"}},
+	})
+	_, err := New().DiskUsage("!", 1).Glob("test-glob")
+	assert.Equal(t, Errors.InvalidCmpOperator, err)
+}
+
+func TestFinder_HasHoles(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("hole probing is available only on linux")
+	}
+	dir := createTestTree(t, map[string]string{"dense": string(make([]byte, 1<<16)), "sparse": "data"})
+	if err := os.Truncate(filepath.Join(dir, "sparse"), 1<<24); err != nil {
+		t.Fatal(err)
+	}
+	result, err := New().HasHoles().Glob(dir + "/*")
+	assert.NoError(t, err)
+	if len(result) == 0 {
+		t.Skip("filesystem of temp dir doesn't support holes")
+	}
+	assert.Equal(t, []string{"sparse"}, getFileNamesFromResult(result))
+	result, err = New().Sparse().Glob(dir + "/*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sparse"}, getFileNamesFromResult(result))
+	filters := New().HasHoles().Sparse().Filters()
+	assert.Equal(t, "HasHoles", filters[1].Name())
+	assert.Equal(t, CostHead, filters[1].Cost())
+}
@@ -0,0 +1,74 @@
+package docmeta
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Info holds document properties. Fields that are missing in document are zero values. Pages is page count for PDF
+// and text documents, slide count for presentations.
+type Info struct {
+	Format    string
+	Pages     int
+	Title     string
+	Author    string
+	Created   time.Time
+	Encrypted bool
+}
+
+var ErrUnknownFormat = errors.New("unknown document format")
+
+var (
+	pdfMagic = []byte("%PDF-")
+	zipMagic = []byte("PK\x03\x04")
+	// compound file binary, used by encrypted OOXML documents
+	cfbMagic = []byte("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1")
+)
+
+// ByPath reads document info from file. Supported formats are PDF, OOXML (docx, xlsx, pptx) and ODF (odt, ods, odp).
+// No external tools are used.
+func ByPath(path string) (result *Info, err error) {
+	var handle *os.File
+	if handle, err = os.Open(path); err != nil {
+		err = errors.Wrap(err, "os.Open")
+		return
+	}
+	defer handle.Close()
+	var stat os.FileInfo
+	if stat, err = handle.Stat(); err != nil {
+		err = errors.Wrap(err, "stat")
+		return
+	}
+	return Read(handle, stat.Size())
+}
+
+// Read reads document info from r which holds size bytes
+func Read(r io.ReaderAt, size int64) (result *Info, err error) {
+	head := make([]byte, 8)
+	if _, err = r.ReadAt(head, 0); err != nil && err != io.EOF {
+		err = errors.Wrap(err, "read")
+		return
+	}
+	err = nil
+	result = &Info{}
+	switch {
+	case bytes.HasPrefix(head, pdfMagic):
+		result.Format = "pdf"
+		err = readPdf(r, size, result)
+	case bytes.HasPrefix(head, zipMagic):
+		err = readZipDocument(r, size, result)
+	case bytes.HasPrefix(head, cfbMagic):
+		err = readEncryptedOoxml(r, size, result)
+	default:
+		err = ErrUnknownFormat
+	}
+	if err != nil {
+		result = nil
+		err = errors.Wrap(err, "read")
+	}
+	return
+}
@@ -0,0 +1,143 @@
+package docmeta
+
+import (
+	"archive/zip"
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByPath_Pdf(t *testing.T) {
+	info, err := ByPath("../test_files/mime/pdf.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &Info{
+		Format:  "pdf",
+		Pages:   2,
+		Title:   "github-git-cheat-sheet",
+		Created: time.Date(2014, 4, 3, 20, 27, 35, 0, time.UTC),
+	}, info)
+}
+
+func TestRead_Pdf(t *testing.T) {
+	body := "%PDF-1.4\n" +
+		"1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n" +
+		"2 0 obj\n<< /Type /Pages /Kids [3 0 R 4 0 R 5 0 R] /Count 3 >>\nendobj\n" +
+		"6 0 obj\n<< /Title <FEFF0054006900740065006C> /Author (Jane \\(JD\\) Doe) /CreationDate (D:20190102030405+02'00') >>\nendobj\n"
+	t.Run("InvalidStartXref", func(t *testing.T) {
+		data := body + "trailer\n<< /Root 1 0 R /Info 6 0 R >>\nstartxref\n999999\n%%EOF\n"
+		info, err := Read(bytes.NewReader([]byte(data)), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 3, info.Pages)
+		assert.Equal(t, "Jane (JD) Doe", info.Author)
+	})
+	t.Run("DeepNesting", func(t *testing.T) {
+		deep := strings.Repeat("[", 1<<20)
+		data := "%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages " + deep + " >>\nendobj\n" +
+			"trailer\n<< /Root 1 0 R >>\nstartxref\n999999\n%%EOF\n"
+		assert.NotPanics(t, func() {
+			Read(bytes.NewReader([]byte(data)), int64(len(data)))
+		})
+		lexer := &pdfLexer{data: []byte(deep)}
+		_, err := lexer.value()
+		assert.Equal(t, errPdfNesting, err)
+	})
+	t.Run("BrokenXrefFallsBackToScan", func(t *testing.T) {
+		data := body + "xref\n0 1\n0000000000 65535 f \ntrailer\n<< /Root 1 0 R /Info 6 0 R >>\nstartxref\n" +
+			strconv.Itoa(len(body)) + "\n%%EOF\n"
+		info, err := Read(bytes.NewReader([]byte(data)), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, &Info{
+			Format:  "pdf",
+			Pages:   3,
+			Title:   "Titel",
+			Author:  "Jane (JD) Doe",
+			Created: time.Date(2019, 1, 2, 3, 4, 5, 0, time.FixedZone("", 2*3600)),
+		}, info)
+	})
+	t.Run("Encrypted", func(t *testing.T) {
+		data := body + "xref\n0 1\n0000000000 65535 f \ntrailer\n<< /Root 1 0 R /Info 6 0 R /Encrypt 7 0 R >>\nstartxref\n" +
+			strconv.Itoa(len(body)) + "\n%%EOF\n"
+		info, err := Read(bytes.NewReader([]byte(data)), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, info.Encrypted)
+		assert.Equal(t, 3, info.Pages)
+		assert.Equal(t, "", info.Title)
+	})
+}
+
+func testZip(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	writer := zip.NewWriter(buf)
+	for name, content := range files {
+		f, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRead_Office(t *testing.T) {
+	testExpectations := []struct {
+		name     string
+		files    map[string]string
+		expected *Info
+	}{
+		{"Ooxml", map[string]string{
+			"[Content_Types].xml": `<Types/>`,
+			"docProps/core.xml": `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties"
+				xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/">
+				<dc:title>Report</dc:title><dc:creator>John</dc:creator>
+				<dcterms:created>2020-03-04T05:06:07Z</dcterms:created></cp:coreProperties>`,
+			"docProps/app.xml": `<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties">
+				<Pages>12</Pages></Properties>`,
+		}, &Info{Format: "ooxml", Pages: 12, Title: "Report", Author: "John",
+			Created: time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC)}},
+		{"Odf", map[string]string{
+			"mimetype": "application/vnd.oasis.opendocument.text",
+			"meta.xml": `<office:document-meta xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+				xmlns:meta="urn:oasis:names:tc:opendocument:xmlns:meta:1.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+				<office:meta><dc:title>Notes</dc:title><meta:initial-creator>Ann</meta:initial-creator>
+				<meta:creation-date>2021-01-02T03:04:05</meta:creation-date>
+				<meta:document-statistic meta:page-count="4"/></office:meta></office:document-meta>`,
+			"META-INF/manifest.xml": `<manifest:manifest/>`,
+		}, &Info{Format: "odf", Pages: 4, Title: "Notes", Author: "Ann",
+			Created: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)}},
+		{"OdfEncrypted", map[string]string{
+			"mimetype":              "application/vnd.oasis.opendocument.text",
+			"meta.xml":              "encrypted garbage",
+			"META-INF/manifest.xml": `<manifest:manifest><manifest:encryption-data/></manifest:manifest>`,
+		}, &Info{Format: "odf", Encrypted: true}},
+	}
+	for _, expectation := range testExpectations {
+		t.Run(expectation.name, func(t *testing.T) {
+			data := testZip(t, expectation.files)
+			info, err := Read(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expectation.expected, info)
+		})
+	}
+	t.Run("PlainZip", func(t *testing.T) {
+		data := testZip(t, map[string]string{"a.txt": "a"})
+		_, err := Read(bytes.NewReader(data), int64(len(data)))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,156 @@
+package docmeta
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// OOXML keeps document properties in docProps/core.xml (Dublin Core) and docProps/app.xml (statistics). ODF keeps
+// them in meta.xml, encryption is declared in META-INF/manifest.xml.
+
+type ooxmlCore struct {
+	Title   string `xml:"http://purl.org/dc/elements/1.1/ title"`
+	Creator string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Created string `xml:"http://purl.org/dc/terms/ created"`
+}
+
+type ooxmlApp struct {
+	Pages  int `xml:"Pages"`
+	Slides int `xml:"Slides"`
+}
+
+type odfMeta struct {
+	Meta struct {
+		Title          string `xml:"http://purl.org/dc/elements/1.1/ title"`
+		Creator        string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+		InitialCreator string `xml:"urn:oasis:names:tc:opendocument:xmlns:meta:1.0 initial-creator"`
+		CreationDate   string `xml:"urn:oasis:names:tc:opendocument:xmlns:meta:1.0 creation-date"`
+		Statistic      struct {
+			PageCount int `xml:"urn:oasis:names:tc:opendocument:xmlns:meta:1.0 page-count,attr"`
+		} `xml:"urn:oasis:names:tc:opendocument:xmlns:meta:1.0 document-statistic"`
+	} `xml:"urn:oasis:names:tc:opendocument:xmlns:office:1.0 meta"`
+}
+
+const maxPropertiesSize = 1 << 20
+
+var errPropertiesNotFound = errors.New("document properties not found")
+
+func readZipDocument(r io.ReaderAt, size int64, info *Info) (err error) {
+	var archive *zip.Reader
+	if archive, err = zip.NewReader(r, size); err != nil {
+		return errors.Wrap(err, "zip")
+	}
+	files := map[string]*zip.File{}
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	switch {
+	case files["docProps/core.xml"] != nil || files["[Content_Types].xml"] != nil:
+		info.Format = "ooxml"
+		return readOoxml(files, info)
+	case files["meta.xml"] != nil || files["mimetype"] != nil:
+		info.Format = "odf"
+		return readOdf(files, info)
+	}
+	return ErrUnknownFormat
+}
+
+func readOoxml(files map[string]*zip.File, info *Info) (err error) {
+	var core ooxmlCore
+	if err = decodeZipXml(files["docProps/core.xml"], &core); err != nil && err != errPropertiesNotFound {
+		return errors.Wrap(err, "core.xml")
+	}
+	info.Title = strings.TrimSpace(core.Title)
+	info.Author = strings.TrimSpace(core.Creator)
+	info.Created = parseXmlDate(core.Created)
+	var app ooxmlApp
+	if err = decodeZipXml(files["docProps/app.xml"], &app); err != nil && err != errPropertiesNotFound {
+		return errors.Wrap(err, "app.xml")
+	}
+	info.Pages = app.Pages
+	if info.Pages == 0 {
+		info.Pages = app.Slides
+	}
+	return nil
+}
+
+func readOdf(files map[string]*zip.File, info *Info) (err error) {
+	var manifest []byte
+	if manifest, err = readZipFile(files["META-INF/manifest.xml"]); err != nil && err != errPropertiesNotFound {
+		return errors.Wrap(err, "manifest.xml")
+	}
+	info.Encrypted = bytes.Contains(manifest, []byte("encryption-data"))
+	var meta odfMeta
+	if err = decodeZipXml(files["meta.xml"], &meta); err != nil {
+		// meta.xml of encrypted documents is usually left in plain text, but it isn't guaranteed
+		if info.Encrypted || err == errPropertiesNotFound {
+			return nil
+		}
+		return errors.Wrap(err, "meta.xml")
+	}
+	info.Title = strings.TrimSpace(meta.Meta.Title)
+	info.Author = strings.TrimSpace(meta.Meta.InitialCreator)
+	if info.Author == "" {
+		info.Author = strings.TrimSpace(meta.Meta.Creator)
+	}
+	info.Created = parseXmlDate(meta.Meta.CreationDate)
+	info.Pages = meta.Meta.Statistic.PageCount
+	return nil
+}
+
+// readEncryptedOoxml recognizes password protected OOXML documents. They are stored as compound file with
+// EncryptedPackage stream and their properties are encrypted.
+func readEncryptedOoxml(r io.ReaderAt, size int64, info *Info) (err error) {
+	marker := []byte("E\x00n\x00c\x00r\x00y\x00p\x00t\x00e\x00d\x00P\x00a\x00c\x00k\x00a\x00g\x00e\x00")
+	readSize := size
+	if readSize > maxPropertiesSize {
+		readSize = maxPropertiesSize
+	}
+	data := make([]byte, readSize)
+	if _, err = r.ReadAt(data, 0); err != nil && err != io.EOF {
+		return errors.Wrap(err, "read")
+	}
+	if !bytes.Contains(data, marker) {
+		return ErrUnknownFormat
+	}
+	info.Format = "ooxml"
+	info.Encrypted = true
+	return nil
+}
+
+func readZipFile(f *zip.File) (data []byte, err error) {
+	if f == nil {
+		return nil, errPropertiesNotFound
+	}
+	var handle io.ReadCloser
+	if handle, err = f.Open(); err != nil {
+		return
+	}
+	defer handle.Close()
+	return ioutil.ReadAll(io.LimitReader(handle, maxPropertiesSize))
+}
+
+func decodeZipXml(f *zip.File, v interface{}) (err error) {
+	var data []byte
+	if data, err = readZipFile(f); err != nil {
+		return
+	}
+	return xml.Unmarshal(data, v)
+}
+
+func parseXmlDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
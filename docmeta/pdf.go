@@ -0,0 +1,560 @@
+package docmeta
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// Minimal PDF reader: it follows startxref to classic cross-reference tables and trailers, reads Info dictionary and
+// page tree root. Files using cross-reference streams fall back to scanning file for object headers. Objects stored
+// in compressed object streams aren't supported, fields stored there are left empty.
+
+type pdfName string
+
+type pdfRef struct {
+	num, gen int
+}
+
+type pdfDict map[pdfName]interface{}
+
+const (
+	pdfTailSize        = 2048
+	pdfObjectChunkSize = 4096
+	pdfScanChunkSize   = 1 << 20
+)
+
+var (
+	pdfObjectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	pdfStartXref    = regexp.MustCompile(`startxref\s+(\d+)`)
+)
+
+type pdfReader struct {
+	r       io.ReaderAt
+	size    int64
+	offsets map[int]int64
+	scanned bool
+}
+
+func readPdf(r io.ReaderAt, size int64, info *Info) (err error) {
+	pdf := &pdfReader{r: r, size: size, offsets: map[int]int64{}}
+	var trailer pdfDict
+	if trailer, err = pdf.trailer(); err != nil {
+		return
+	}
+	if _, found := trailer["Encrypt"]; found {
+		info.Encrypted = true
+	}
+	if root, ok := pdf.resolve(trailer["Root"]).(pdfDict); ok {
+		if pages, ok := pdf.resolve(root["Pages"]).(pdfDict); ok {
+			if count, ok := pdf.resolve(pages["Count"]).(int); ok {
+				info.Pages = count
+			}
+		}
+	}
+	// strings of encrypted documents are encrypted as well
+	if infoDict, ok := pdf.resolve(trailer["Info"]).(pdfDict); ok && !info.Encrypted {
+		info.Title = pdfText(pdf.resolve(infoDict["Title"]))
+		info.Author = pdfText(pdf.resolve(infoDict["Author"]))
+		info.Created = pdfDate(pdfText(pdf.resolve(infoDict["CreationDate"])))
+	}
+	return
+}
+
+// trailer returns merged trailer dictionaries following /Prev chain. Newer entries take precedence.
+func (p *pdfReader) trailer() (trailer pdfDict, err error) {
+	tailOffset := p.size - pdfTailSize
+	if tailOffset < 0 {
+		tailOffset = 0
+	}
+	var tail []byte
+	if tail, err = p.readAt(tailOffset, int(p.size-tailOffset)); err != nil {
+		return
+	}
+	matches := pdfStartXref.FindAllSubmatch(tail, -1)
+	if matches == nil {
+		return nil, errors.New("startxref not found")
+	}
+	xrefOffset, _ := strconv.ParseInt(string(matches[len(matches)-1][1]), 10, 64)
+	trailer = pdfDict{}
+	visited := map[int64]bool{}
+	for xrefOffset > 0 && xrefOffset < p.size && !visited[xrefOffset] {
+		visited[xrefOffset] = true
+		var section pdfDict
+		if section, err = p.xrefSection(xrefOffset); err != nil {
+			return
+		}
+		for key, value := range section {
+			if _, found := trailer[key]; !found {
+				trailer[key] = value
+			}
+		}
+		prev, _ := section["Prev"].(int)
+		xrefOffset = int64(prev)
+	}
+	delete(trailer, "Prev")
+	if len(trailer) == 0 {
+		// broken startxref, use last trailer keyword instead
+		if idx := bytes.LastIndex(tail, []byte("trailer")); idx >= 0 {
+			lexer := &pdfLexer{data: tail, pos: idx + len("trailer")}
+			if value, valueErr := lexer.value(); valueErr == nil {
+				trailer, _ = value.(pdfDict)
+			}
+		}
+	}
+	if trailer == nil {
+		err = errors.New("trailer not found")
+	}
+	return
+}
+
+// xrefSection reads cross-reference table at offset and returns trailer following it. For cross-reference streams
+// dictionary of the stream is returned, it holds the same keys as trailer. Data is read in growing chunks as table
+// size isn't known upfront.
+func (p *pdfReader) xrefSection(offset int64) (trailer pdfDict, err error) {
+	for size := pdfObjectChunkSize * 16; ; size *= 4 {
+		var data []byte
+		if data, err = p.readAt(offset, size); err != nil {
+			return
+		}
+		trailer, err = p.parseXrefSection(data)
+		if errors.Cause(err) != io.ErrUnexpectedEOF || offset+int64(size) >= p.size {
+			return
+		}
+	}
+}
+
+func (p *pdfReader) parseXrefSection(data []byte) (trailer pdfDict, err error) {
+	lexer := &pdfLexer{data: data}
+	if !bytes.HasPrefix(data, []byte("xref")) {
+		var value interface{}
+		if _, value, err = lexer.object(); err != nil {
+			return nil, errors.Wrap(err, "xref stream")
+		}
+		trailer, _ = value.(pdfDict)
+		return
+	}
+	lexer.pos = len("xref")
+	offsets := map[int]int64{}
+	for {
+		lexer.skipSpace()
+		if lexer.pos >= len(lexer.data) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if lexer.hasPrefix("trailer") {
+			lexer.pos += len("trailer")
+			var value interface{}
+			if value, err = lexer.value(); err != nil {
+				return nil, errors.Wrap(err, "trailer")
+			}
+			trailer, _ = value.(pdfDict)
+			break
+		}
+		first, firstErr := lexer.integer()
+		count, countErr := lexer.integer()
+		if firstErr != nil || countErr != nil {
+			return nil, errors.New("invalid xref subsection")
+		}
+		for i := 0; i < count; i++ {
+			lexer.skipSpace()
+			if lexer.pos+18 > len(lexer.data) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			entry := lexer.data[lexer.pos : lexer.pos+18]
+			lexer.pos += 18
+			if entry[17] == 'n' {
+				offsets[first+i], _ = strconv.ParseInt(string(entry[:10]), 10, 64)
+			}
+		}
+	}
+	// sections are read from newest to oldest, entries already known are newer
+	for num, offset := range offsets {
+		if _, found := p.offsets[num]; !found {
+			p.offsets[num] = offset
+		}
+	}
+	return
+}
+
+// resolve follows indirect references
+func (p *pdfReader) resolve(value interface{}) interface{} {
+	for depth := 0; depth < 8; depth++ {
+		ref, isRef := value.(pdfRef)
+		if !isRef {
+			return value
+		}
+		value = p.object(ref)
+	}
+	return nil
+}
+
+func (p *pdfReader) object(ref pdfRef) interface{} {
+	offset, found := p.offsets[ref.num]
+	if !found && !p.scanned {
+		p.scan()
+		offset, found = p.offsets[ref.num]
+	}
+	if !found {
+		return nil
+	}
+	size := int64(pdfObjectChunkSize)
+	for {
+		if offset+size > p.size {
+			size = p.size - offset
+		}
+		data, err := p.readAt(offset, int(size))
+		if err != nil {
+			return nil
+		}
+		lexer := &pdfLexer{data: data}
+		_, value, err := lexer.object()
+		if err == nil || err == errPdfNesting || offset+size >= p.size {
+			return value
+		}
+		size *= 4
+	}
+}
+
+// scan indexes object headers in whole file. It's used when cross-reference table can't be read.
+func (p *pdfReader) scan() {
+	p.scanned = true
+	const overlap = 64
+	for offset := int64(0); offset < p.size; offset += pdfScanChunkSize - overlap {
+		data, err := p.readAt(offset, pdfScanChunkSize)
+		if err != nil && err != io.EOF {
+			return
+		}
+		for _, match := range pdfObjectHeader.FindAllSubmatchIndex(data, -1) {
+			// object header has to start a line
+			if match[0] > 0 && data[match[0]-1] != '\n' && data[match[0]-1] != '\r' {
+				continue
+			}
+			num, _ := strconv.Atoi(string(data[match[2]:match[3]]))
+			// later definitions override earlier ones, like in incremental updates
+			p.offsets[num] = offset + int64(match[0])
+		}
+		if int64(len(data)) < pdfScanChunkSize {
+			return
+		}
+	}
+}
+
+func (p *pdfReader) readAt(offset int64, length int) (data []byte, err error) {
+	if remaining := p.size - offset; int64(length) > remaining {
+		length = int(remaining)
+	}
+	if length < 0 {
+		return nil, errors.New("offset out of range")
+	}
+	data = make([]byte, length)
+	var n int
+	if n, err = p.r.ReadAt(data, offset); n == length {
+		err = nil
+	}
+	return data[:n], err
+}
+
+// maxPdfNesting limits depth of nested dictionaries and arrays, so crafted files can't exhaust stack
+const maxPdfNesting = 64
+
+var errPdfNesting = errors.New("objects nested too deep")
+
+type pdfLexer struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+// nest enters nested dictionary or array. Returned function leaves it.
+func (l *pdfLexer) nest() (leave func(), err error) {
+	if l.depth >= maxPdfNesting {
+		return nil, errPdfNesting
+	}
+	l.depth++
+	return func() { l.depth-- }, nil
+}
+
+func isPdfWhitespace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPdfDelimiter(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isPdfWhitespace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+func (l *pdfLexer) hasPrefix(prefix string) bool {
+	return bytes.HasPrefix(l.data[l.pos:], []byte(prefix))
+}
+
+func (l *pdfLexer) token() string {
+	l.skipSpace()
+	start := l.pos
+	for l.pos < len(l.data) && !isPdfWhitespace(l.data[l.pos]) && !isPdfDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+func (l *pdfLexer) integer() (int, error) {
+	return strconv.Atoi(l.token())
+}
+
+// object reads "num gen obj value" structure
+func (l *pdfLexer) object() (ref pdfRef, value interface{}, err error) {
+	if ref.num, err = l.integer(); err != nil {
+		return
+	}
+	if ref.gen, err = l.integer(); err != nil {
+		return
+	}
+	if l.token() != "obj" {
+		err = errors.New("obj keyword expected")
+		return
+	}
+	value, err = l.value()
+	return
+}
+
+func (l *pdfLexer) value() (value interface{}, err error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	switch c := l.data[l.pos]; {
+	case l.hasPrefix("<<"):
+		return l.dict()
+	case c == '<':
+		return l.hexString()
+	case c == '(':
+		return l.literalString()
+	case c == '[':
+		return l.array()
+	case c == '/':
+		return l.name(), nil
+	}
+	token := l.token()
+	switch token {
+	case "":
+		return nil, errors.New("unexpected character")
+	case "true", "false":
+		return token == "true", nil
+	case "null":
+		return nil, nil
+	}
+	num, numErr := strconv.Atoi(token)
+	if numErr != nil {
+		return strconv.ParseFloat(token, 64)
+	}
+	// integer could be start of "num gen R" reference
+	saved := l.pos
+	if gen, genErr := l.integer(); genErr == nil && l.token() == "R" {
+		return pdfRef{num, gen}, nil
+	}
+	l.pos = saved
+	return num, nil
+}
+
+func (l *pdfLexer) dict() (value interface{}, err error) {
+	var leave func()
+	if leave, err = l.nest(); err != nil {
+		return
+	}
+	defer leave()
+	l.pos += 2
+	dict := pdfDict{}
+	for {
+		l.skipSpace()
+		if l.hasPrefix(">>") {
+			l.pos += 2
+			return dict, nil
+		}
+		if l.pos >= len(l.data) || l.data[l.pos] != '/' {
+			return nil, errors.New("dictionary key expected")
+		}
+		key := l.name()
+		if dict[key], err = l.value(); err != nil {
+			return
+		}
+	}
+}
+
+func (l *pdfLexer) array() (value interface{}, err error) {
+	var leave func()
+	if leave, err = l.nest(); err != nil {
+		return
+	}
+	defer leave()
+	l.pos++
+	var array []interface{}
+	for {
+		l.skipSpace()
+		if l.hasPrefix("]") {
+			l.pos++
+			return array, nil
+		}
+		var item interface{}
+		if item, err = l.value(); err != nil {
+			return
+		}
+		array = append(array, item)
+	}
+}
+
+func (l *pdfLexer) name() pdfName {
+	l.pos++
+	var name []byte
+	for l.pos < len(l.data) && !isPdfWhitespace(l.data[l.pos]) && !isPdfDelimiter(l.data[l.pos]) {
+		c := l.data[l.pos]
+		if c == '#' && l.pos+2 < len(l.data) {
+			if decoded, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				name = append(name, byte(decoded))
+				l.pos += 3
+				continue
+			}
+		}
+		name = append(name, c)
+		l.pos++
+	}
+	return pdfName(name)
+}
+
+func (l *pdfLexer) hexString() (value interface{}, err error) {
+	end := bytes.IndexByte(l.data[l.pos:], '>')
+	if end < 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	var digits []byte
+	for _, c := range l.data[l.pos+1 : l.pos+end] {
+		if !isPdfWhitespace(c) {
+			digits = append(digits, c)
+		}
+	}
+	l.pos += end + 1
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	result := make([]byte, len(digits)/2)
+	for i := range result {
+		b, parseErr := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		if parseErr != nil {
+			return nil, errors.Wrap(parseErr, "hex string")
+		}
+		result[i] = byte(b)
+	}
+	return string(result), nil
+}
+
+var pdfEscapes = map[byte]byte{'n': '\n', 'r': '\r', 't': '\t', 'b': '\b', 'f': '\f', '(': '(', ')': ')', '\\': '\\'}
+
+func (l *pdfLexer) literalString() (value interface{}, err error) {
+	l.pos++
+	var result []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return string(result), nil
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			next := l.data[l.pos]
+			l.pos++
+			if escaped, found := pdfEscapes[next]; found {
+				result = append(result, escaped)
+				continue
+			}
+			if next >= '0' && next <= '7' {
+				octal := int(next - '0')
+				for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+					octal = octal*8 + int(l.data[l.pos]-'0')
+					l.pos++
+				}
+				result = append(result, byte(octal))
+				continue
+			}
+			// backslash followed by end of line is line continuation
+			if next == '\r' && l.pos < len(l.data) && l.data[l.pos] == '\n' {
+				l.pos++
+			}
+			if next != '\r' && next != '\n' {
+				result = append(result, next)
+			}
+			continue
+		}
+		result = append(result, c)
+	}
+	return nil, io.ErrUnexpectedEOF
+}
+
+// pdfText decodes text string which is either UTF-16BE with byte order mark or PDFDocEncoding (treated as Latin-1)
+func pdfText(value interface{}) string {
+	s, ok := value.(string)
+	if !ok {
+		return ""
+	}
+	if len(s) >= 2 && s[0] == 0xfe && s[1] == 0xff {
+		units := make([]uint16, 0, len(s)/2)
+		for i := 2; i+1 < len(s); i += 2 {
+			units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+	runes := make([]rune, len(s))
+	for i := 0; i < len(s); i++ {
+		runes[i] = rune(s[i])
+	}
+	return string(runes)
+}
+
+var pdfDatePattern = regexp.MustCompile(`^(?:D:)?(\d{4})(\d{2})?(\d{2})?(\d{2})?(\d{2})?(\d{2})?(?:([+\-Z])(\d{2})?'?(\d{2})?'?)?`)
+
+// pdfDate parses "D:YYYYMMDDHHmmSSOHH'mm'" date, every part after year is optional
+func pdfDate(s string) time.Time {
+	m := pdfDatePattern.FindStringSubmatch(s)
+	if m == nil {
+		return time.Time{}
+	}
+	part := func(idx, def int) int {
+		if v, err := strconv.Atoi(m[idx]); err == nil {
+			return v
+		}
+		return def
+	}
+	location := time.UTC
+	if m[7] == "+" || m[7] == "-" {
+		offset := part(8, 0)*3600 + part(9, 0)*60
+		if m[7] == "-" {
+			offset = -offset
+		}
+		location = time.FixedZone("", offset)
+	}
+	return time.Date(part(1, 0), time.Month(part(2, 1)), part(3, 1), part(4, 0), part(5, 0), part(6, 0), 0, location)
+}
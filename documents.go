@@ -0,0 +1,91 @@
+package finder
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/duffpl/go-finder/docmeta"
+	"github.com/duffpl/go-finder/file"
+)
+
+const documentInfoAttr = "document-info"
+
+// DocumentInfo returns page count, title, author, dates and encryption flag of PDF, OOXML or ODF document. PDF info
+// comes from trailer and page tree, office formats are read from their properties parts.
+func DocumentInfo(fiex file.FileInfoEx) (info *docmeta.Info, err error) {
+	var v interface{}
+	if v, err = file.Attr(fiex, documentInfoAttr, func(path string) (interface{}, error) {
+		return docmeta.ByPath(path)
+	}); err != nil {
+		return
+	}
+	info = v.(*docmeta.Info)
+	return
+}
+
+func (f *Finder) addDocumentInfoFilter(name string, cb func(info *docmeta.Info) bool) {
+	f.addInfoFilter(name, CostHead, func(fiex file.FileInfoEx) (interface{}, error) {
+		return DocumentInfo(fiex)
+	}, func(info interface{}) bool {
+		return cb(info.(*docmeta.Info))
+	})
+}
+
+// PageCount adds matching against number of pages (slides for presentations) of document. Valid operators are
+// available in CmpOperator const.
+func (f *Finder) PageCount(cmpOp CmpOperator, pages int) *Finder {
+	if f.lastErr != nil { return f }
+	if !isCmpOperatorValid(cmpOp) {
+		f.lastErr = Errors.InvalidCmpOperator
+		return f
+	}
+	f.addDocumentInfoFilter("PageCount", func(info *docmeta.Info) bool {
+		return compareInt64(cmpOp, int64(info.Pages), int64(pages))
+	})
+	return f
+}
+
+// DocumentTitle adds matching against document title using regexp pattern
+func (f *Finder) DocumentTitle(pattern string) *Finder {
+	if f.lastErr != nil { return f }
+	var compiled *regexp.Regexp
+	if compiled, f.lastErr = regexp.Compile(pattern); f.lastErr != nil {
+		return f
+	}
+	f.addDocumentInfoFilter("DocumentTitle", func(info *docmeta.Info) bool {
+		return compiled.MatchString(info.Title)
+	})
+	return f
+}
+
+// DocumentAuthor adds matching against document author using regexp pattern
+func (f *Finder) DocumentAuthor(pattern string) *Finder {
+	if f.lastErr != nil { return f }
+	var compiled *regexp.Regexp
+	if compiled, f.lastErr = regexp.Compile(pattern); f.lastErr != nil {
+		return f
+	}
+	f.addDocumentInfoFilter("DocumentAuthor", func(info *docmeta.Info) bool {
+		return compiled.MatchString(info.Author)
+	})
+	return f
+}
+
+// DocumentCreatedBetween adds matching against creation date stored in document. Range includes from and excludes
+// to. Zero value of either bound leaves range open on that side. Documents without creation date aren't matched.
+func (f *Finder) DocumentCreatedBetween(from, to time.Time) *Finder {
+	if f.lastErr != nil { return f }
+	f.addDocumentInfoFilter("DocumentCreatedBetween", func(info *docmeta.Info) bool {
+		return inTimeRange(info.Created, from, to)
+	})
+	return f
+}
+
+// Encrypted adds matching of password protected documents
+func (f *Finder) Encrypted() *Finder {
+	if f.lastErr != nil { return f }
+	f.addDocumentInfoFilter("Encrypted", func(info *docmeta.Info) bool {
+		return info.Encrypted
+	})
+	return f
+}
@@ -0,0 +1,35 @@
+package finder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/duffpl/go-finder/docmeta"
+	"github.com/duffpl/go-finder/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinder_DocumentInfoFilters(t *testing.T) {
+	mockGlob := newMockGlobFunc([]file.FileInfoEx{
+		withAttr("report", documentInfoAttr, &docmeta.Info{Pages: 40, Title: "Annual report", Author: "Finance",
+			Created: time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)}),
+		withAttr("memo", documentInfoAttr, &docmeta.Info{Pages: 1, Title: "Memo", Author: "HR", Encrypted: true}),
+	})
+	runFilterExpectations(t, mockGlob, "test-glob", []filterExpectation{
+		{"PageCount", New().PageCount(MoreThan, 10), []string{"report"}},
+		{"DocumentTitle", New().DocumentTitle("(?i)report"), []string{"report"}},
+		{"DocumentAuthor", New().DocumentAuthor("^HR$"), []string{"memo"}},
+		{"DocumentCreatedBetween", New().DocumentCreatedBetween(time.Time{}, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)), []string{"report"}},
+		{"Encrypted", New().Encrypted(), []string{"memo"}},
+	})
+}
+
+func TestFinder_PageCount_integration(t *testing.T) {
+	result, err := New().
+		PageCount(Equal, 2).
+		Glob("./test_files/mime/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"pdf.pdf"}, getFileNamesFromResult(result))
+}
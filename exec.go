@@ -0,0 +1,243 @@
+package finder
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/pkg/errors"
+)
+
+// Placeholders replaced in command arguments: {} and {path} with absolute path, {dir} with its directory, {base} with
+// file name, {ext} with extension (including dot) and {stem} with file name without extension. If no argument
+// contains placeholder, path is appended as last argument.
+var execPlaceholder = regexp.MustCompile(`\{(path|dir|base|ext|stem)?\}`)
+
+// DefaultExecMaxArgSize limits size of arguments (and environment) of single batch when ExecCommand.MaxArgSize is not
+// set. It's well below ARG_MAX of common systems.
+const DefaultExecMaxArgSize = 128 * 1024
+
+// ExecCommand describes external command run for matched files
+type ExecCommand struct {
+	Name string
+	Args []string
+	// Dir is working directory of command, current one if empty
+	Dir string
+	// Parallelism limits number of commands running at once. Zero means checker concurrency.
+	Parallelism int
+	// MaxArgSize limits size of arguments and environment of single batch run by ExecBatch
+	MaxArgSize int
+}
+
+// ExecResult is outcome of single command invocation. ExitCode is -1 if command couldn't be started, Err is set for
+// non zero exit status too.
+type ExecResult struct {
+	Paths    []string
+	Args     []string
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+	Err      error
+}
+
+// ExecReport is result of Exec and ExecBatch. Results are sorted by first path.
+type ExecReport struct {
+	Results []ExecResult
+	Failed  int
+}
+
+// Exec runs command for every file matched by pattern, like find -exec cmd {} \;. Commands are started once all files
+// are matched, at most ExecCommand.Parallelism at once.
+func (f *Finder) Exec(pattern string, cmd ExecCommand) (report *ExecReport, err error) {
+	var globResult []file.FileInfoEx
+	if globResult, err = f.Glob(pattern); err != nil {
+		return
+	}
+	report = &ExecReport{Results: make([]ExecResult, len(globResult))}
+	runParallel(f.execParallelism(cmd), len(globResult), func(idx int) {
+		path := fileInfoExPath(globResult[idx])
+		report.Results[idx] = runExec(cmd, expandExecArgs(cmd.Args, path), []string{path})
+	})
+	report.finish()
+	return
+}
+
+// ExecBatch runs command with as many matched files as fit into argument size limit, like find -exec cmd {} +. Only
+// {} placeholder is allowed, it's expanded to all paths of batch. Paths are sorted and batches run in parallel.
+func (f *Finder) ExecBatch(pattern string, cmd ExecCommand) (report *ExecReport, err error) {
+	placeholderIdx := -1
+	for idx, arg := range cmd.Args {
+		if arg == "{}" && placeholderIdx == -1 {
+			placeholderIdx = idx
+		} else if execPlaceholder.MatchString(arg) {
+			return nil, errors.Errorf("ExecBatch supports only single {} argument, got %q", arg)
+		}
+	}
+	var paths []string
+	var pathsMu sync.Mutex
+	if err = f.GlobEach(pattern, func(info file.FileInfoEx) {
+		pathsMu.Lock()
+		paths = append(paths, fileInfoExPath(info))
+		pathsMu.Unlock()
+	}); err != nil {
+		return
+	}
+	sort.Strings(paths)
+	fixedArgs := cmd.Args
+	if placeholderIdx >= 0 {
+		fixedArgs = append(append([]string{}, cmd.Args[:placeholderIdx]...), cmd.Args[placeholderIdx+1:]...)
+	}
+	var batches [][]string
+	if batches, err = execBatches(paths, cmd, fixedArgs); err != nil {
+		return
+	}
+	report = &ExecReport{Results: make([]ExecResult, len(batches))}
+	runParallel(f.execParallelism(cmd), len(batches), func(idx int) {
+		args := append([]string{}, cmd.Args...)
+		if placeholderIdx >= 0 {
+			args = append(append(append([]string{}, cmd.Args[:placeholderIdx]...), batches[idx]...), cmd.Args[placeholderIdx+1:]...)
+		} else {
+			args = append(args, batches[idx]...)
+		}
+		report.Results[idx] = runExec(cmd, args, batches[idx])
+	})
+	report.finish()
+	return
+}
+
+// ExecFilter adds matching of files for which command exits with zero status, non zero status rejects file. Command
+// that can't be started (e.g. missing binary) is reported as filter error. Arguments may use placeholders (see Exec).
+// Filter runs after all built-in ones as it's the most expensive. Command runs in current directory and as many
+// commands run at once as there are checkers (see SetCheckerConcurrency), ExecCommand knobs don't apply.
+func (f *Finder) ExecFilter(name string, args ...string) *Finder {
+	if f.lastErr != nil { return f }
+	cmd := ExecCommand{Name: name, Args: args}
+	f.addFilter("ExecFilter", CostExternal, func(fiex file.FileInfoEx) (result bool, err error) {
+		path := fileInfoExPath(fiex)
+		execResult := runExec(cmd, expandExecArgs(args, path), []string{path})
+		if execResult.ExitCode == -1 {
+			return false, execResult.Err
+		}
+		return execResult.ExitCode == 0, nil
+	})
+	return f
+}
+
+type execAction struct {
+	cmd ExecCommand
+}
+
+// ExecAction runs command for every file passed to Finder.Apply. Command output is discarded, non zero exit status is
+// reported as error. Use Finder.Exec when output is needed.
+func ExecAction(name string, args ...string) Action {
+	return execAction{ExecCommand{Name: name, Args: args}}
+}
+
+func (a execAction) Describe(path, rel string) string {
+	return "exec " + strings.Join(append([]string{a.cmd.Name}, expandExecArgs(a.cmd.Args, path)...), " ")
+}
+
+func (a execAction) Apply(info file.FileInfoEx, path, rel string) error {
+	return runExec(a.cmd, expandExecArgs(a.cmd.Args, path), []string{path}).Err
+}
+
+func (f *Finder) execParallelism(cmd ExecCommand) int {
+	if cmd.Parallelism > 0 {
+		return cmd.Parallelism
+	}
+	return f.numCheckers
+}
+
+func (r *ExecReport) finish() {
+	sort.SliceStable(r.Results, func(i, j int) bool {
+		return len(r.Results[i].Paths) > 0 && len(r.Results[j].Paths) > 0 && r.Results[i].Paths[0] < r.Results[j].Paths[0]
+	})
+	for _, result := range r.Results {
+		if result.Err != nil {
+			r.Failed++
+		}
+	}
+}
+
+func expandExecArgs(args []string, path string) (expanded []string) {
+	base := filepath.Base(path)
+	ext := filepath.Ext(path)
+	replacer := strings.NewReplacer(
+		"{}", path,
+		"{path}", path,
+		"{dir}", filepath.Dir(path),
+		"{base}", base,
+		"{ext}", ext,
+		"{stem}", strings.TrimSuffix(base, ext),
+	)
+	hasPlaceholder := false
+	for _, arg := range args {
+		hasPlaceholder = hasPlaceholder || execPlaceholder.MatchString(arg)
+		expanded = append(expanded, replacer.Replace(arg))
+	}
+	if !hasPlaceholder {
+		expanded = append(expanded, path)
+	}
+	return
+}
+
+// execBatches splits paths so size of every command line (with environment) stays within limit. Every argument costs
+// its length, terminating NUL and pointer.
+func execBatches(paths []string, cmd ExecCommand, fixedArgs []string) (batches [][]string, err error) {
+	limit := cmd.MaxArgSize
+	if limit <= 0 {
+		limit = DefaultExecMaxArgSize
+	}
+	argSize := func(arg string) int {
+		return len(arg) + 1 + 8
+	}
+	fixed := argSize(cmd.Name)
+	for _, arg := range fixedArgs {
+		fixed += argSize(arg)
+	}
+	for _, arg := range os.Environ() {
+		fixed += argSize(arg)
+	}
+	var batch []string
+	size := fixed
+	for _, path := range paths {
+		if fixed+argSize(path) > limit {
+			return nil, errors.Errorf("path %s doesn't fit into argument size limit", path)
+		}
+		if size+argSize(path) > limit {
+			batches = append(batches, batch)
+			batch, size = nil, fixed
+		}
+		batch = append(batch, path)
+		size += argSize(path)
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return
+}
+
+func runExec(cmd ExecCommand, args, paths []string) (result ExecResult) {
+	result = ExecResult{Paths: paths, Args: args}
+	command := exec.Command(cmd.Name, args...)
+	command.Dir = cmd.Dir
+	var stdout, stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	err := command.Run()
+	result.Stdout, result.Stderr = stdout.Bytes(), stderr.Bytes()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		result.ExitCode = exitErr.ExitCode()
+		result.Err = errors.Wrap(err, cmd.Name)
+	} else if err != nil {
+		result.ExitCode = -1
+		result.Err = errors.Wrap(err, cmd.Name)
+	}
+	return
+}
@@ -0,0 +1,88 @@
+package finder
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandExecArgs(t *testing.T) {
+	assert.Equal(t,
+		[]string{"/a/b/c.tar.gz", "/a/b", "c.tar.gz", ".gz", "c.tar", "--out=/a/b"},
+		expandExecArgs([]string{"{}", "{dir}", "{base}", "{ext}", "{stem}", "--out={dir}"}, "/a/b/c.tar.gz"))
+	assert.Equal(t, []string{"-l", "/a/b"}, expandExecArgs([]string{"-l"}, "/a/b"))
+}
+
+func TestExecBatches(t *testing.T) {
+	env := 0
+	for _, e := range os.Environ() {
+		env += len(e) + 9
+	}
+	fixed := len("echo") + 9 + env
+	paths := []string{"/aaa", "/bbb", "/ccc"}
+	batches, err := execBatches(paths, ExecCommand{Name: "echo", MaxArgSize: fixed + 2*13}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"/aaa", "/bbb"}, {"/ccc"}}, batches)
+	_, err = execBatches(paths, ExecCommand{Name: "echo", MaxArgSize: fixed + 12}, nil)
+	assert.Error(t, err)
+	args := make([]string, 1, 2)
+	args[0] = "-n"
+	_, err = execBatches(paths, ExecCommand{Name: "echo", Args: args}, args)
+	assert.NoError(t, err)
+	assert.Equal(t, "", args[:2][1])
+}
+
+func TestFinder_Exec(t *testing.T) {
+	dir := createActionsTree(t)
+	t.Run("PerFile", func(t *testing.T) {
+		report, err := New().RegexpName(`\.txt$`).Exec(dir+"/src/**", ExecCommand{Name: "sh", Args: []string{"-c", `echo "$1"; test "$1" = a`, "sh", "{stem}"}})
+		assert.NoError(t, err)
+		if assert.Len(t, report.Results, 2) {
+			assert.Equal(t, "a\n", string(report.Results[0].Stdout))
+			assert.Equal(t, 0, report.Results[0].ExitCode)
+			assert.Equal(t, []string{dir + "/src/sub/b.txt"}, report.Results[1].Paths)
+			assert.Equal(t, 1, report.Results[1].ExitCode)
+			assert.Error(t, report.Results[1].Err)
+		}
+		assert.Equal(t, 1, report.Failed)
+	})
+	t.Run("Batch", func(t *testing.T) {
+		report, err := New().Exec(dir+"/src/**/*.txt", ExecCommand{Name: "/nonexistent-command"})
+		assert.NoError(t, err)
+		assert.Equal(t, -1, report.Results[0].ExitCode)
+		report, err = New().ExecBatch(dir+"/src/**/*.txt", ExecCommand{Name: "echo", Args: []string{"{}", "end"}})
+		assert.NoError(t, err)
+		if assert.Len(t, report.Results, 1) {
+			assert.Equal(t, dir+"/src/a.txt "+dir+"/src/sub/b.txt end\n", string(report.Results[0].Stdout))
+		}
+		_, err = New().ExecBatch(dir+"/src/**", ExecCommand{Name: "echo", Args: []string{"{dir}"}})
+		assert.Error(t, err)
+	})
+	t.Run("Filter", func(t *testing.T) {
+		result, err := New().ExecFilter("grep", "-q", "b", "{}").Glob(dir + "/src/**")
+		assert.NoError(t, err)
+		var names []string
+		for _, fiex := range result {
+			if !fiex.IsDir() {
+				names = append(names, fiex.Name())
+			}
+		}
+		assert.Equal(t, []string{"b.txt"}, names)
+
+		sut := New().CollectStats().ExecFilter("finder-test-missing-binary")
+		result, err = sut.Glob(dir + "/src/*.txt")
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+		assert.Equal(t, int64(1), sut.Stats().Filters[0].Errors)
+	})
+	t.Run("Action", func(t *testing.T) {
+		report, err := New().Apply(dir+"/src/c.log", ExecAction("test", "-s"), ActionOptions{DryRun: true})
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(report.Results[0].Description, "exec test -s "))
+		report, err = New().Apply(dir+"/src/c.log", ExecAction("test", "-s"), ActionOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Applied)
+	})
+}
@@ -2,6 +2,8 @@ package file
 
 import (
 	"os"
+
+	"github.com/duffpl/go-finder/mimechecker"
 )
 
 type FileInfoEx interface {
@@ -11,3 +13,75 @@ type FileInfoEx interface {
 	Mime() (m string, err error)
 }
 
+// StatFileInfoEx is optional extension of FileInfoEx which reports platform specific status. See StatOf.
+type StatFileInfoEx interface {
+	FileInfoEx
+	// Stat returns device, inode and link count. ok is false on platforms where they aren't available.
+	Stat() (stat Stat, ok bool)
+}
+
+// CountedChecksumFileInfoEx is optional extension of FileInfoEx which reports reads done to compute checksum. Items
+// created by lazy constructors implement it.
+type CountedChecksumFileInfoEx interface {
+	FileInfoEx
+	// CountedChecksum works like Checksum. Bytes read are reported to counter (if not nil) as they are read, cached is
+	// true if checksum didn't have to be computed.
+	CountedChecksum(counter func(n int64)) (cs []byte, cached bool, err error)
+}
+
+// CountedChecksum returns checksum of fiex reporting bytes read to counter. Items not implementing
+// CountedChecksumFileInfoEx report nothing and are never treated as cached.
+func CountedChecksum(fiex FileInfoEx, counter func(n int64)) (cs []byte, cached bool, err error) {
+	if counted, ok := fiex.(CountedChecksumFileInfoEx); ok {
+		return counted.CountedChecksum(counter)
+	}
+	cs, err = fiex.Checksum()
+	return
+}
+
+// MimeResultFileInfoEx is optional extension of FileInfoEx which reports MIME detection details
+type MimeResultFileInfoEx interface {
+	FileInfoEx
+	// MimeResult returns MIME type with parsed parameters and detection details
+	MimeResult() (r *mimechecker.Result, err error)
+}
+
+// MimeResult returns MIME detection details of fiex. Items not implementing MimeResultFileInfoEx report parsed value
+// of Mime with unknown confidence.
+func MimeResult(fiex FileInfoEx) (r *mimechecker.Result, err error) {
+	if detailed, ok := fiex.(MimeResultFileInfoEx); ok {
+		return detailed.MimeResult()
+	}
+	var m string
+	if m, err = fiex.Mime(); err != nil {
+		return
+	}
+	r = mimechecker.ParseResult(m, "", mimechecker.ConfidenceUnknown)
+	return
+}
+
+// AttrFileInfoEx is optional extension of FileInfoEx which caches computed attributes. Items created by lazy
+// constructors implement it.
+type AttrFileInfoEx interface {
+	FileInfoEx
+	// Attr returns value of named attribute. Value is computed with callback on first call and cached for subsequent
+	// ones. If callback is nil only cached value is returned (nil if attribute hasn't been computed yet).
+	Attr(name string, cb AttrCallback) (v interface{}, err error)
+}
+
+// Attr returns named attribute of fiex using its cache if it implements AttrFileInfoEx. Otherwise value is computed
+// with callback on every call (nil is returned for nil callback).
+func Attr(fiex FileInfoEx, name string, cb AttrCallback) (v interface{}, err error) {
+	if cached, ok := fiex.(AttrFileInfoEx); ok {
+		return cached.Attr(name, cb)
+	}
+	if cb == nil {
+		return
+	}
+	var abs string
+	if abs, err = fiex.Abs(); err != nil {
+		return
+	}
+	return cb(abs)
+}
+
@@ -0,0 +1,6 @@
+package file
+
+import "errors"
+
+// ErrHolesUnsupported is returned by HasHoles on platforms without SEEK_HOLE support
+var ErrHolesUnsupported = errors.New("hole probing is not supported on this platform")
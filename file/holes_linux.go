@@ -0,0 +1,63 @@
+//go:build linux
+// +build linux
+
+package file
+
+import (
+	"io"
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// whence values of lseek, not exported by syscall package
+const (
+	seekData = 3
+	seekHole = 4
+)
+
+// HasHoles probes file with SEEK_DATA and SEEK_HOLE. Leading hole is found by first data being past offset 0 (or
+// missing at all), other holes by first hole after it being before end of file. Filesystems without hole support
+// report whole file as single data extent, so such files are never reported as having holes.
+func HasHoles(path string) (result bool, err error) {
+	var handle *os.File
+	if handle, err = os.Open(path); err != nil {
+		err = errors.Wrap(err, "os.Open")
+		return
+	}
+	defer handle.Close()
+	var size, hole int64
+	if size, err = handle.Seek(0, io.SeekEnd); err != nil {
+		err = errors.Wrap(err, "seek end")
+		return
+	}
+	if size == 0 {
+		return
+	}
+	var data int64
+	if data, err = handle.Seek(0, seekData); err != nil {
+		if errors.Is(err, syscall.ENXIO) {
+			// no data at all, file is one big hole
+			return true, nil
+		}
+		err = seekError(err, "seek data")
+		return
+	}
+	if data > 0 {
+		return true, nil
+	}
+	if hole, err = handle.Seek(data, seekHole); err != nil {
+		err = seekError(err, "seek hole")
+		return
+	}
+	return hole < size, nil
+}
+
+// seekError reports kernels and filesystems rejecting SEEK_DATA/SEEK_HOLE whence as ErrHolesUnsupported
+func seekError(err error, message string) error {
+	if errors.Is(err, syscall.EINVAL) {
+		return ErrHolesUnsupported
+	}
+	return errors.Wrap(err, message)
+}
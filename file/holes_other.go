@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package file
+
+// HasHoles isn't supported outside Linux, ErrHolesUnsupported is always returned
+func HasHoles(path string) (bool, error) {
+	return false, ErrHolesUnsupported
+}
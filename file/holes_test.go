@@ -0,0 +1,66 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasHoles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "holes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dense := filepath.Join(dir, "dense")
+	if err = ioutil.WriteFile(dense, make([]byte, 1<<16), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sparse := filepath.Join(dir, "sparse")
+	if err = ioutil.WriteFile(sparse, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Truncate(sparse, 1<<24); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "linux" {
+		_, err = HasHoles(sparse)
+		assert.Equal(t, ErrHolesUnsupported, err)
+		return
+	}
+	holes, err := HasHoles(dense)
+	assert.NoError(t, err)
+	assert.False(t, holes)
+	holes, err = HasHoles(sparse)
+	assert.NoError(t, err)
+	if !holes {
+		t.Skip("filesystem of temp dir doesn't support holes")
+	}
+	leading := filepath.Join(dir, "leading")
+	handle, err := os.Create(leading)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = handle.WriteAt([]byte("data"), 1<<24)
+	handle.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	holes, err = HasHoles(leading)
+	assert.NoError(t, err)
+	assert.True(t, holes)
+	empty := filepath.Join(dir, "all-hole")
+	if err = ioutil.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Truncate(empty, 1<<20); err != nil {
+		t.Fatal(err)
+	}
+	holes, err = HasHoles(empty)
+	assert.NoError(t, err)
+	assert.True(t, holes)
+}
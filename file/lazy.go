@@ -1,27 +1,45 @@
 package file
 
 import (
+	"github.com/duffpl/go-finder/mimechecker"
 	"github.com/pkg/errors"
 	"os"
 	"path/filepath"
+	"sync"
 )
 
 type ChecksumCallback func(path string) ([]byte, error)
+
+// CountingChecksumCallback works like ChecksumCallback but reports bytes to counter (if not nil) as they are read
+type CountingChecksumCallback func(path string, counter func(n int64)) ([]byte, error)
 type MimeCallback func(path string) (string, error)
+type MimeResultCallback func(path string) (*mimechecker.Result, error)
+type AttrCallback func(path string) (interface{}, error)
 
 type lazyFileInfo struct {
 	os.FileInfo
 
 	abs      string
-	mime     string
-	checksum []byte
+	mime       string
+	mimeResult *mimechecker.Result
+	checksum   []byte
+
+	attrsMu sync.Mutex
+	attrs   map[string]interface{}
 
-	mimeCallback     MimeCallback
-	checksumCallback ChecksumCallback
+	mimeCallback       MimeCallback
+	mimeResultCallback MimeResultCallback
+	checksumCallback   CountingChecksumCallback
 }
 
 func (f *lazyFileInfo) Mime() (result string, err error) {
-	if f.mime == "" {
+	if f.mime == "" && f.mimeResultCallback != nil {
+		var mimeResult *mimechecker.Result
+		if mimeResult, err = f.MimeResult(); err != nil {
+			return
+		}
+		f.mime = mimeResult.String()
+	} else if f.mime == "" {
 		f.mime, err = f.mimeCallback(f.abs)
 		if err != nil {
 			err = errors.Wrap(err, "mime")
@@ -32,24 +50,107 @@ func (f *lazyFileInfo) Mime() (result string, err error) {
 	return
 }
 
+// MimeResult uses result callback if set. Otherwise value returned by mime callback is parsed and reported with
+// unknown confidence.
+func (f *lazyFileInfo) MimeResult() (result *mimechecker.Result, err error) {
+	if f.mimeResult == nil && f.mimeResultCallback != nil {
+		if f.mimeResult, err = f.mimeResultCallback(f.abs); err != nil {
+			err = errors.Wrap(err, "mime")
+			return
+		}
+	} else if f.mimeResult == nil {
+		var m string
+		if m, err = f.Mime(); err != nil {
+			return
+		}
+		f.mimeResult = mimechecker.ParseResult(m, "", mimechecker.ConfidenceUnknown)
+	}
+	result = f.mimeResult
+	return
+}
+
 func (f *lazyFileInfo) Abs() (result string, err error) {
 	return f.abs, nil
 }
 
 func (f *lazyFileInfo) Checksum() (result []byte, err error) {
+	result, _, err = f.CountedChecksum(nil)
+	return
+}
+
+func (f *lazyFileInfo) CountedChecksum(counter func(n int64)) (result []byte, cached bool, err error) {
 	if f.checksum == nil {
-		f.checksum, err = f.checksumCallback(f.abs)
+		f.checksum, err = f.checksumCallback(f.abs, counter)
 		if err != nil {
 			err = errors.Wrap(err, "checksum")
 			return
 		}
+	} else {
+		cached = true
 	}
 	result = f.checksum
 	return
 }
 
+func (f *lazyFileInfo) Stat() (Stat, bool) {
+	return StatOf(f.FileInfo)
+}
+
+func (f *lazyFileInfo) Attr(name string, cb AttrCallback) (result interface{}, err error) {
+	f.attrsMu.Lock()
+	result, found := f.attrs[name]
+	f.attrsMu.Unlock()
+	if found || cb == nil {
+		return
+	}
+	if result, err = cb(f.abs); err != nil {
+		err = errors.Wrap(err, "attr "+name)
+		return
+	}
+	f.attrsMu.Lock()
+	if f.attrs == nil {
+		f.attrs = map[string]interface{}{}
+	}
+	f.attrs[name] = result
+	f.attrsMu.Unlock()
+	return
+}
+
 // NewLazyFileInfoExByPath creates new lazyFileInfo instance
 func NewLazyFileInfoExByPath(path string, csCb ChecksumCallback, mCb MimeCallback) (result FileInfoEx, err error) {
+	return newLazyFileInfoEx(path, &lazyFileInfo{mimeCallback: mCb, checksumCallback: CountingChecksum(csCb)})
+}
+
+// NewLazyFileInfoExWithMimeResult creates new lazyFileInfo instance which MIME type is detected by mrCb. Mime returns
+// formatted result.
+func NewLazyFileInfoExWithMimeResult(path string, csCb ChecksumCallback, mrCb MimeResultCallback) (result FileInfoEx, err error) {
+	return newLazyFileInfoEx(path, &lazyFileInfo{mimeResultCallback: mrCb, checksumCallback: CountingChecksum(csCb)})
+}
+
+// NewLazyFileInfoExCounting works like NewLazyFileInfoExWithMimeResult but checksum callback reports bytes it reads, see
+// CountedChecksum
+func NewLazyFileInfoExCounting(path string, csCb CountingChecksumCallback, mrCb MimeResultCallback) (result FileInfoEx, err error) {
+	return newLazyFileInfoEx(path, &lazyFileInfo{mimeResultCallback: mrCb, checksumCallback: csCb})
+}
+
+// CountingChecksum adapts checksum callback which can't report its reads. Whole file is assumed to be read, so its size
+// is reported once checksum is computed.
+func CountingChecksum(cb ChecksumCallback) CountingChecksumCallback {
+	if cb == nil {
+		return nil
+	}
+	return func(path string, counter func(n int64)) (checksum []byte, err error) {
+		if checksum, err = cb(path); err != nil || counter == nil {
+			return
+		}
+		if fi, statErr := os.Stat(path); statErr == nil {
+			counter(fi.Size())
+		}
+		return
+	}
+}
+
+func newLazyFileInfoEx(path string, info *lazyFileInfo) (result FileInfoEx, err error) {
 	var (
 		stat os.FileInfo
 		abs  string
@@ -62,11 +163,8 @@ func NewLazyFileInfoExByPath(path string, csCb ChecksumCallback, mCb MimeCallbac
 		err = errors.Wrap(err, "filepath.Abs")
 		return
 	}
-	result = &lazyFileInfo{
-		FileInfo:         stat,
-		abs:              abs,
-		mimeCallback:     mCb,
-		checksumCallback: csCb,
-	}
+	info.FileInfo = stat
+	info.abs = abs
+	result = info
 	return
 }
@@ -3,6 +3,7 @@ package file
 import (
 	"testing"
 	"github.com/stretchr/testify/assert"
+	"github.com/duffpl/go-finder/mimechecker"
 )
 
 func TestNewLazyByPath(t *testing.T) {
@@ -28,4 +29,79 @@ func TestNewLazyByPath(t *testing.T) {
 			assert.Equal(t, expected, actual)
 		})
 	})
-}
\ No newline at end of file
+}
+func TestLazyFileInfo_MimeResult(t *testing.T) {
+	path := "../test_files/checksum/3b5d5c3712955042212316173ccf37be"
+	t.Run("ParsedMime", func(t *testing.T) {
+		info, _ := NewLazyFileInfoExByPath(path, nil, func(path string) (string, error) {
+			return "text/plain; charset=utf-8", nil
+		})
+		result, _ := MimeResult(info)
+		assert.Equal(t, "text/plain", result.MediaType)
+		assert.Equal(t, map[string]string{"charset": "utf-8"}, result.Params)
+	})
+	t.Run("ResultCallback", func(t *testing.T) {
+		calls := 0
+		expected := &mimechecker.Result{MediaType: "image/png", Checker: "test", Confidence: 1}
+		info, _ := NewLazyFileInfoExWithMimeResult(path, nil, func(path string) (*mimechecker.Result, error) {
+			calls++
+			return expected, nil
+		})
+		result, _ := MimeResult(info)
+		m, _ := info.Mime()
+		assert.Equal(t, expected, result)
+		assert.Equal(t, "image/png", m)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestLazyFileInfo_Attr(t *testing.T) {
+	info, _ := NewLazyFileInfoExByPath("../test_files/checksum/3b5d5c3712955042212316173ccf37be", nil, nil)
+	calls := 0
+	cb := func(path string) (interface{}, error) {
+		calls++
+		return path, nil
+	}
+	cached, _ := Attr(info, "test", nil)
+	assert.Nil(t, cached)
+	first, _ := Attr(info, "test", cb)
+	second, _ := Attr(info, "test", cb)
+	abs, _ := info.Abs()
+	assert.Equal(t, abs, first)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, calls)
+	cached, _ = Attr(info, "test", nil)
+	assert.Equal(t, abs, cached)
+}
+
+// plainFileInfoEx hides optional extensions of wrapped FileInfoEx
+type plainFileInfoEx struct {
+	FileInfoEx
+}
+
+func TestAttr_withoutCache(t *testing.T) {
+	lazy, _ := NewLazyFileInfoExByPath("../test_files/checksum/3b5d5c3712955042212316173ccf37be", nil, nil)
+	info := plainFileInfoEx{lazy}
+	calls := 0
+	cb := func(path string) (interface{}, error) {
+		calls++
+		return path, nil
+	}
+	cached, _ := Attr(info, "test", nil)
+	assert.Nil(t, cached)
+	Attr(info, "test", cb)
+	value, _ := Attr(info, "test", cb)
+	abs, _ := info.Abs()
+	assert.Equal(t, abs, value)
+	assert.Equal(t, 2, calls)
+}
+
+func TestMimeResult_withoutDetails(t *testing.T) {
+	lazy, _ := NewLazyFileInfoExByPath("../test_files/checksum/3b5d5c3712955042212316173ccf37be", nil, func(path string) (string, error) {
+		return "text/plain; charset=utf-8", nil
+	})
+	result, err := MimeResult(plainFileInfoEx{lazy})
+	assert.NoError(t, err)
+	assert.Equal(t, "text/plain", result.MediaType)
+	assert.Equal(t, mimechecker.ConfidenceUnknown, result.Confidence)
+}
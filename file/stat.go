@@ -0,0 +1,80 @@
+package file
+
+import (
+	"os"
+	"sync"
+)
+
+// Stat holds platform specific file status. It's available only where Sys() of os.FileInfo can be interpreted
+// (currently Linux).
+type Stat struct {
+	Dev   uint64
+	Ino   uint64
+	Nlink uint64
+	Uid   uint32
+	Gid   uint32
+	// Blocks is number of allocated 512 byte blocks
+	Blocks int64
+}
+
+// Allocated returns number of bytes allocated on disk
+func (s Stat) Allocated() int64 {
+	return s.Blocks * 512
+}
+
+// StatOf extracts platform specific status from FileInfo. Status reported by StatFileInfoEx is preferred over Sys().
+// ok is false if it isn't available.
+func StatOf(fi os.FileInfo) (stat Stat, ok bool) {
+	if ex, isEx := fi.(StatFileInfoEx); isEx {
+		return ex.Stat()
+	}
+	return statOf(fi)
+}
+
+type inodeKey struct {
+	dev, ino uint64
+}
+
+type inodeChecksum struct {
+	once     sync.Once
+	checksum []byte
+	err      error
+}
+
+// PerInodeChecksum wraps checksum callback so every inode is hashed once no matter how many hard links point to it.
+// Paths without inode information are passed to callback directly.
+func PerInodeChecksum(cb ChecksumCallback) ChecksumCallback {
+	counting := PerInodeCountingChecksum(CountingChecksum(cb))
+	return func(path string) ([]byte, error) {
+		return counting(path, nil)
+	}
+}
+
+// PerInodeCountingChecksum works like PerInodeChecksum for counting callbacks. Reads are reported only to counter of
+// call which actually hashed inode.
+func PerInodeCountingChecksum(cb CountingChecksumCallback) CountingChecksumCallback {
+	var mu sync.Mutex
+	entries := map[inodeKey]*inodeChecksum{}
+	return func(path string, counter func(n int64)) ([]byte, error) {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return cb(path, counter)
+		}
+		stat, ok := StatOf(fi)
+		if !ok {
+			return cb(path, counter)
+		}
+		key := inodeKey{stat.Dev, stat.Ino}
+		mu.Lock()
+		entry := entries[key]
+		if entry == nil {
+			entry = &inodeChecksum{}
+			entries[key] = entry
+		}
+		mu.Unlock()
+		entry.once.Do(func() {
+			entry.checksum, entry.err = cb(path, counter)
+		})
+		return entry.checksum, entry.err
+	}
+}
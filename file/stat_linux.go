@@ -0,0 +1,25 @@
+//go:build linux
+// +build linux
+
+package file
+
+import (
+	"os"
+	"syscall"
+)
+
+func statOf(fi os.FileInfo) (stat Stat, ok bool) {
+	var sys *syscall.Stat_t
+	if sys, ok = fi.Sys().(*syscall.Stat_t); !ok {
+		return
+	}
+	stat = Stat{
+		Dev:    uint64(sys.Dev),
+		Ino:    uint64(sys.Ino),
+		Nlink:  uint64(sys.Nlink),
+		Uid:    sys.Uid,
+		Gid:    sys.Gid,
+		Blocks: int64(sys.Blocks),
+	}
+	return
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package file
+
+import "os"
+
+func statOf(fi os.FileInfo) (stat Stat, ok bool) {
+	return
+}
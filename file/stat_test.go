@@ -0,0 +1,76 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createHardLinks(t *testing.T) (dir string) {
+	if runtime.GOOS != "linux" {
+		t.Skip("inode information is available only on linux")
+	}
+	dir, err := ioutil.TempDir("", "stat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, "a"), []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Link(filepath.Join(dir, "a"), filepath.Join(dir, "b")); err != nil {
+		t.Skip(err)
+	}
+	return
+}
+
+func TestStatOf(t *testing.T) {
+	dir := createHardLinks(t)
+	defer os.RemoveAll(dir)
+	a, _ := NewLazyFileInfoExByPath(filepath.Join(dir, "a"), nil, nil)
+	b, _ := NewLazyFileInfoExByPath(filepath.Join(dir, "b"), nil, nil)
+	statA, okA := StatOf(a)
+	statB, okB := StatOf(b)
+	assert.True(t, okA && okB)
+	assert.Equal(t, statA, statB)
+	statSys, _ := StatOf(plainFileInfoEx{a})
+	assert.Equal(t, statA, statSys)
+	assert.Equal(t, uint64(2), statA.Nlink)
+	assert.NotZero(t, statA.Ino)
+}
+
+func TestPerInodeChecksum(t *testing.T) {
+	dir := createHardLinks(t)
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "c"), []byte("content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	calls := map[string]int{}
+	cb := PerInodeChecksum(func(path string) ([]byte, error) {
+		mu.Lock()
+		calls[filepath.Base(path)]++
+		mu.Unlock()
+		return []byte(filepath.Base(path)), nil
+	})
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		for _, name := range []string{"a", "b", "c"} {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				cb(filepath.Join(dir, name))
+			}(name)
+		}
+	}
+	wg.Wait()
+	assert.Equal(t, 1, calls["a"]+calls["b"])
+	assert.Equal(t, 1, calls["c"])
+	missing, err := cb(filepath.Join(dir, "missing"))
+	assert.Equal(t, []byte("missing"), missing)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,12 @@
+package file
+
+import "errors"
+
+// ErrXattrUnsupported is returned by Xattrs on platforms without extended attribute support
+var ErrXattrUnsupported = errors.New("extended attributes are not supported on this platform")
+
+// POSIX ACLs are stored as extended attributes. They exist only when ACL extends permission bits.
+const (
+	XattrACLAccess  = "system.posix_acl_access"
+	XattrACLDefault = "system.posix_acl_default"
+)
@@ -0,0 +1,67 @@
+//go:build linux
+// +build linux
+
+package file
+
+import (
+	"bytes"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// Xattrs reads all extended attributes of file. Filesystems without xattr support yield empty map. Attributes that
+// can't be read due to permissions are skipped.
+func Xattrs(path string) (result map[string][]byte, err error) {
+	result = map[string][]byte{}
+	var names []byte
+	if names, err = readXattr(func(dest []byte) (int, error) {
+		return syscall.Listxattr(path, dest)
+	}); err != nil {
+		if isXattrUnsupported(err) {
+			err = nil
+			return
+		}
+		err = errors.Wrap(err, "listxattr")
+		return
+	}
+	for _, name := range bytes.Split(names, []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		value, getErr := readXattr(func(dest []byte) (int, error) {
+			return syscall.Getxattr(path, string(name), dest)
+		})
+		if getErr != nil {
+			if getErr == syscall.ENODATA || getErr == syscall.EACCES || getErr == syscall.EPERM {
+				continue
+			}
+			err = errors.Wrap(getErr, "getxattr "+string(name))
+			return
+		}
+		result[string(name)] = value
+	}
+	return
+}
+
+// readXattr asks for size first and retries if attribute grew in the meantime
+func readXattr(call func(dest []byte) (int, error)) (data []byte, err error) {
+	for {
+		var size int
+		if size, err = call(nil); err != nil || size == 0 {
+			return
+		}
+		data = make([]byte, size)
+		if size, err = call(data); err == syscall.ERANGE {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	}
+}
+
+func isXattrUnsupported(err error) bool {
+	return err == syscall.ENOTSUP || err == syscall.EOPNOTSUPP
+}
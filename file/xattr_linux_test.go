@@ -0,0 +1,36 @@
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXattrs(t *testing.T) {
+	handle, err := ioutil.TempFile("", "xattr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle.Close()
+	defer os.Remove(handle.Name())
+	xattrs, err := Xattrs(handle.Name())
+	assert.NoError(t, err)
+	assert.Empty(t, xattrs)
+	if err = syscall.Setxattr(handle.Name(), "user.origin", []byte("upload"), 0); err != nil {
+		t.Skip("filesystem of temp dir doesn't support user xattrs: ", err)
+	}
+	if err = syscall.Setxattr(handle.Name(), "user.empty", nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	xattrs, err = Xattrs(handle.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("upload"), xattrs["user.origin"])
+	value, found := xattrs["user.empty"]
+	assert.True(t, found)
+	assert.Empty(t, value)
+	_, err = Xattrs(handle.Name() + "-missing")
+	assert.Error(t, err)
+}
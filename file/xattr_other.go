@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package file
+
+// Xattrs isn't supported outside Linux, ErrXattrUnsupported is always returned
+func Xattrs(path string) (map[string][]byte, error) {
+	return nil, ErrXattrUnsupported
+}
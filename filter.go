@@ -0,0 +1,140 @@
+package finder
+
+import (
+	"sort"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/pkg/errors"
+)
+
+// Cost is class of work filter has to do to check single file. Finder checks cheaper filters first, so expensive ones
+// run only for files that passed all others. Within same class filters with fewer needs go first, e.g. CostMetadata
+// filter that doesn't need stat runs before one that does. Otherwise filters are checked in order they were added.
+type Cost int
+
+const (
+	// CostName filters use only path and name of file
+	CostName Cost = iota
+	// CostMetadata filters use stat and other metadata (xattrs, link counts, allocation) that don't read content
+	CostMetadata
+	// CostHead filters read beginning or selected parts of file (magic numbers, headers, tags)
+	CostHead
+	// CostContent filters read whole file (checksums, text analysis, decoding)
+	CostContent
+	// CostExternal filters run external programs
+	CostExternal
+)
+
+var costNames = map[Cost]string{
+	CostName:     "name",
+	CostMetadata: "metadata",
+	CostHead:     "head",
+	CostContent:  "content",
+	CostExternal: "external",
+}
+
+func (c Cost) String() string {
+	return costNames[c]
+}
+
+// Needs is set of lazy FileInfoEx attributes filter uses. Flags are ordered by cost of fetching them, so greater value
+// means more expensive needs.
+type Needs int
+
+const (
+	NeedStat Needs = 1 << iota
+	NeedHead
+	NeedContent
+)
+
+// Has checks if all needs of other are included
+func (n Needs) Has(other Needs) bool {
+	return n&other == other
+}
+
+// Filter is predicate checked for globbed files. Match returning error rejects file.
+type Filter interface {
+	Name() string
+	Cost() Cost
+	Needs() Needs
+	Match(fiex file.FileInfoEx) (bool, error)
+}
+
+type funcFilter struct {
+	name  string
+	cost  Cost
+	needs Needs
+	match func(fiex file.FileInfoEx) (bool, error)
+}
+
+// NewFilter creates Filter from match function
+func NewFilter(name string, cost Cost, needs Needs, match func(fiex file.FileInfoEx) (bool, error)) Filter {
+	return &funcFilter{name, cost, needs, match}
+}
+
+func (f *funcFilter) Name() string { return f.name }
+
+func (f *funcFilter) Cost() Cost { return f.cost }
+
+func (f *funcFilter) Needs() Needs { return f.needs }
+
+func (f *funcFilter) Match(fiex file.FileInfoEx) (bool, error) { return f.match(fiex) }
+
+// defaultNeeds are attributes usually needed by filters of given cost
+func (c Cost) defaultNeeds() (needs Needs) {
+	switch c {
+	case CostName:
+	case CostMetadata:
+		needs = NeedStat
+	case CostHead:
+		needs = NeedHead
+	default:
+		needs = NeedContent
+	}
+	return
+}
+
+// Where adds user defined filter. It's scheduled by its cost together with built-in filters.
+func (f *Finder) Where(filter Filter) *Finder {
+	if f.lastErr != nil { return f }
+	if filter == nil {
+		f.lastErr = errors.New("filter must not be nil")
+		return f
+	}
+	f.addFilterOf(filter)
+	return f
+}
+
+// Filters returns filters in order they are checked
+func (f *Finder) Filters() []Filter {
+	return append([]Filter{}, f.filters...)
+}
+
+// addInfoFilter adds filter passing value returned by info accessor (ImageInfo, MediaInfo, ...) to cb. Accessor
+// errors reject file and are wrapped with filter name.
+func (f *Finder) addInfoFilter(name string, cost Cost, info func(fiex file.FileInfoEx) (interface{}, error), cb func(info interface{}) bool) {
+	f.addFilter(name, cost, func(fiex file.FileInfoEx) (result bool, err error) {
+		var v interface{}
+		if v, err = info(fiex); err != nil {
+			err = errors.Wrap(err, name)
+			return
+		}
+		result = cb(v)
+		return
+	})
+}
+
+func (f *Finder) addFilter(name string, cost Cost, callback func(fiex file.FileInfoEx) (bool, error)) {
+	f.addFilterOf(NewFilter(name, cost, cost.defaultNeeds(), callback))
+}
+
+func (f *Finder) addFilterOf(filter Filter) {
+	f.filters = append(f.filters, filter)
+	sort.SliceStable(f.filters, func(i, j int) bool {
+		a, b := f.filters[i], f.filters[j]
+		if a.Cost() != b.Cost() {
+			return a.Cost() < b.Cost()
+		}
+		return a.Needs() < b.Needs()
+	})
+}
@@ -0,0 +1,60 @@
+package finder
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinder_Where(t *testing.T) {
+	mockGlob := newMockGlobFunc([]file.FileInfoEx{
+		&mockFileInfoEx{name: "a.txt", size: 100, checksum: []byte{1}},
+		&mockFileInfoEx{name: "b.txt", size: 10, checksum: []byte{2}},
+		&mockFileInfoEx{name: "c.log", size: 100, checksum: []byte{1}},
+	})
+	var checkedMu sync.Mutex
+	var checked []string
+	expensive := NewFilter("expensive", CostContent, NeedContent, func(fiex file.FileInfoEx) (bool, error) {
+		checkedMu.Lock()
+		checked = append(checked, fiex.Name())
+		checkedMu.Unlock()
+		return fiex.Name() != "c.log", nil
+	})
+	sut := New().SetGlobFunc(mockGlob).
+		Where(expensive).
+		Checksum("01").
+		Size(MoreThan, 50).
+		RegexpName(`\.txt$`)
+	result, err := sut.Glob("*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.txt"}, getFileNamesFromResult(result))
+	assert.Equal(t, []string{"a.txt"}, checked)
+
+	var names []string
+	var costs []Cost
+	for _, filter := range sut.Filters() {
+		names = append(names, filter.Name())
+		costs = append(costs, filter.Cost())
+	}
+	assert.Equal(t, []string{"RegexpName", "Size", "expensive", "Checksum"}, names)
+	assert.Equal(t, []Cost{CostName, CostMetadata, CostContent, CostContent}, costs)
+	assert.True(t, sut.Filters()[1].Needs().Has(NeedStat))
+	assert.Equal(t, "content", CostContent.String())
+}
+
+func TestFinder_Where_needsOrder(t *testing.T) {
+	match := func(fiex file.FileInfoEx) (bool, error) { return true, nil }
+	sut := New().
+		Where(NewFilter("head", CostMetadata, NeedHead, match)).
+		Where(NewFilter("stat", CostMetadata, NeedStat, match)).
+		Where(NewFilter("none", CostMetadata, 0, match))
+	var names []string
+	for _, filter := range sut.Filters() {
+		names = append(names, filter.Name())
+	}
+	assert.Equal(t, []string{"none", "stat", "head"}, names)
+	_, err := New().Where(nil).Glob("*")
+	assert.Error(t, err)
+}
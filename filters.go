@@ -3,11 +3,15 @@ package finder
 import (
 	"regexp"
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/duffpl/go-finder/file"
+	"github.com/duffpl/go-finder/checksum"
+	"github.com/duffpl/go-finder/mimechecker"
 )
-// CmpOperator is string const enum for Size filter
+// CmpOperator is string const enum for comparison filters like Size
 type CmpOperator string
 
 const (
@@ -20,14 +24,16 @@ const (
 
 var Errors = struct{
 	InvalidSizeOperator error
+	InvalidCmpOperator  error
 }{
 	InvalidSizeOperator: errors.New("invalid size operator"),
+	InvalidCmpOperator:  errors.New("invalid comparison operator"),
 }
 
 // Checksum adds matching against checksum. Expected checksum should be hex encoded string
 func (f *Finder) Checksum(hexChecksum string) *Finder {
 	if f.lastErr != nil { return f }
-	f.addFilter(func(fiex file.FileInfoEx) (result bool, err error) {
+	f.addFilter("Checksum", CostContent, func(fiex file.FileInfoEx) (result bool, err error) {
 		var fileChecksum []byte
 		if fileChecksum, err = fiex.Checksum(); err != nil {
 			err = errors.Wrap(err, "checksum")
@@ -35,10 +41,121 @@ func (f *Finder) Checksum(hexChecksum string) *Finder {
 		}
 		result = fmt.Sprintf("%x", fileChecksum) == hexChecksum
 		return
-	}, 100)
+	})
 	return f
 }
 
+// ChecksumIn adds matching against set of checksums. Algorithm is guessed from length of digests in set: SHA-1,
+// SHA-256 and SHA-512 digests are computed for matched files (and cached on FileInfoEx), others are compared against
+// FileInfoEx.Checksum (MD5 with default glob function). Files which checksum length differs from set are reported as
+// errors. If every digest in set has known file size, files of other sizes are rejected before they are hashed. Empty
+// set matches nothing and no file is hashed.
+func (f *Finder) ChecksumIn(set *checksum.Set) *Finder {
+	if f.lastErr != nil { return f }
+	if set == nil {
+		f.lastErr = errors.New("ChecksumIn: nil set")
+		return f
+	}
+	if set.DigestSize() < 0 {
+		f.lastErr = errors.New("ChecksumIn: digests of different lengths")
+		return f
+	}
+	if set.DigestSize() == 0 {
+		f.addFilter("ChecksumIn", CostName, func(fiex file.FileInfoEx) (bool, error) {
+			return false, nil
+		})
+		return f
+	}
+	algorithm, _ := set.Algorithm()
+	if set.HasSizes() {
+		f.addFilter("ChecksumIn size", CostMetadata, func(fiex file.FileInfoEx) (bool, error) {
+			return set.MayContainSize(fiex.Size()), nil
+		})
+	}
+	f.addFilter("ChecksumIn", CostContent, func(fiex file.FileInfoEx) (result bool, err error) {
+		var fileChecksum []byte
+		switch algorithm {
+		case checksum.SHA1, checksum.SHA256, checksum.SHA512:
+			fileChecksum, err = algorithmChecksum(fiex, algorithm)
+		default:
+			fileChecksum, err = fiex.Checksum()
+		}
+		if err != nil {
+			err = errors.Wrap(err, "checksum")
+			return
+		}
+		if len(fileChecksum) != set.DigestSize() {
+			err = errors.Errorf("checksum: digest length %d doesn't match set digest length %d", len(fileChecksum), set.DigestSize())
+			return
+		}
+		result = set.Contains(fileChecksum)
+		return
+	})
+	return f
+}
+
+// algorithmChecksum returns digest of file computed with given algorithm, cached as "checksum:<algorithm>" attribute
+func algorithmChecksum(fiex file.FileInfoEx, algorithm checksum.Algorithm) (digest []byte, err error) {
+	var v interface{}
+	if v, err = file.Attr(fiex, "checksum:"+string(algorithm), func(path string) (interface{}, error) {
+		return algorithm.ByPath(path)
+	}); err != nil {
+		return
+	}
+	digest = v.([]byte)
+	return
+}
+
+const (
+	fuzzyHashAttr        = "fuzzy-hash"
+	similarityAttrPrefix = "similarity:"
+)
+
+// SimilarTo adds matching against fuzzy hash (see checksum.Fuzzy) of reference file. Matches files with similarity
+// score (0-100) equal or above threshold. Score can be read from result items with SimilarityScore.
+func (f *Finder) SimilarTo(path string, threshold int) *Finder {
+	if f.lastErr != nil { return f }
+	var refAbs, refHash string
+	if refAbs, f.lastErr = filepath.Abs(path); f.lastErr != nil {
+		f.lastErr = errors.Wrap(f.lastErr, "SimilarTo")
+		return f
+	}
+	if refHash, f.lastErr = checksum.FuzzyByPath(refAbs); f.lastErr != nil {
+		f.lastErr = errors.Wrap(f.lastErr, "SimilarTo")
+		return f
+	}
+	f.addFilter("SimilarTo", CostContent, func(fiex file.FileInfoEx) (result bool, err error) {
+		var score interface{}
+		if score, err = file.Attr(fiex, similarityAttrPrefix+refAbs, func(string) (interface{}, error) {
+			hash, err := file.Attr(fiex, fuzzyHashAttr, func(path string) (interface{}, error) {
+				return checksum.FuzzyByPath(path)
+			})
+			if err != nil {
+				return nil, err
+			}
+			return checksum.FuzzyCompare(refHash, hash.(string))
+		}); err != nil {
+			err = errors.Wrap(err, "SimilarTo")
+			return
+		}
+		result = score.(int) >= threshold
+		return
+	})
+	return f
+}
+
+// SimilarityScore returns score computed for result item by SimilarTo filter with given reference file. ok is false
+// if score hasn't been computed.
+func SimilarityScore(fiex file.FileInfoEx, referencePath string) (score int, ok bool) {
+	refAbs, err := filepath.Abs(referencePath)
+	if err != nil {
+		return
+	}
+	v, _ := file.Attr(fiex, similarityAttrPrefix+refAbs, nil)
+	score, ok = v.(int)
+	return
+}
+
 func isCmpOperatorValid(cmpOp CmpOperator) bool {
 	validOperators := []CmpOperator{MoreThan, MoreOrEqual, LessThan, LessOrEqual, Equal}
 	for _, vop := range validOperators {
@@ -58,38 +175,115 @@ func (f *Finder) Size(cmpOp CmpOperator, cmpSize int64) *Finder {
 		f.lastErr = Errors.InvalidSizeOperator
 		return f
 	}
-	f.addFilter(func(info file.FileInfoEx) (cmpResult bool, err error) {
-		size := info.Size()
-		switch cmpOp {
-		case MoreThan:
-			cmpResult = size > cmpSize
-		case MoreOrEqual:
-			cmpResult = size >= cmpSize
-		case LessThan:
-			cmpResult = size < cmpSize
-		case LessOrEqual:
-			cmpResult = size <= cmpSize
-		case Equal:
-			cmpResult = size == cmpSize
-		}
-		return
-	}, 1)
+	f.addFilter("Size", CostMetadata, func(info file.FileInfoEx) (bool, error) {
+		return compareInt64(cmpOp, info.Size(), cmpSize), nil
+	})
 	return f
 }
 
-// Mime adds matching against MIME type of file
-func (f *Finder) Mime(mimeType string) *Finder {
+// HardLinks adds matching against number of hard links to file. Valid operators are available in CmpOperator const.
+// Files without link count information (non Linux platforms) aren't matched.
+func (f *Finder) HardLinks(cmpOp CmpOperator, links int) *Finder {
 	if f.lastErr != nil { return f }
-	f.addFilter(func(ex file.FileInfoEx) (result bool, err error) {
-		var mimeResult string
-		if mimeResult, err = ex.Mime(); err != nil {
+	if !isCmpOperatorValid(cmpOp) {
+		f.lastErr = Errors.InvalidCmpOperator
+		return f
+	}
+	f.addFilter("HardLinks", CostMetadata, func(info file.FileInfoEx) (bool, error) {
+		stat, ok := file.StatOf(info)
+		return ok && compareInt64(cmpOp, int64(stat.Nlink), int64(links)), nil
+	})
+	return f
+}
+
+func compareInt64(cmpOp CmpOperator, value, cmpValue int64) (cmpResult bool) {
+	switch cmpOp {
+	case MoreThan:
+		cmpResult = value > cmpValue
+	case MoreOrEqual:
+		cmpResult = value >= cmpValue
+	case LessThan:
+		cmpResult = value < cmpValue
+	case LessOrEqual:
+		cmpResult = value <= cmpValue
+	case Equal:
+		cmpResult = value == cmpValue
+	}
+	return
+}
+
+func compareFloat64(cmpOp CmpOperator, value, cmpValue float64) (cmpResult bool) {
+	switch cmpOp {
+	case MoreThan:
+		cmpResult = value > cmpValue
+	case MoreOrEqual:
+		cmpResult = value >= cmpValue
+	case LessThan:
+		cmpResult = value < cmpValue
+	case LessOrEqual:
+		cmpResult = value <= cmpValue
+	case Equal:
+		cmpResult = value == cmpValue
+	}
+	return
+}
+
+// MimeOption modifies matching done by Mime filter
+type MimeOption func(m *mimeMatch)
+
+type mimeMatch struct {
+	ignoreParams  bool
+	minConfidence float64
+}
+
+// IgnoreMimeParams makes Mime compare only media type, so "text/plain" matches "text/plain; charset=utf-8"
+func IgnoreMimeParams() MimeOption {
+	return func(m *mimeMatch) {
+		m.ignoreParams = true
+	}
+}
+
+// MinMimeConfidence makes Mime reject types detected with confidence lower than given one (0-1)
+func MinMimeConfidence(confidence float64) MimeOption {
+	return func(m *mimeMatch) {
+		m.minConfidence = confidence
+	}
+}
+
+// Mime adds matching against MIME type of file. Type or subtype may be replaced with wildcard, e.g. "image/*".
+// Parameters have to be equal unless IgnoreMimeParams option is used.
+func (f *Finder) Mime(mimeType string, opts ...MimeOption) *Finder {
+	if f.lastErr != nil { return f }
+	match := &mimeMatch{}
+	for _, opt := range opts {
+		opt(match)
+	}
+	pattern := mimechecker.ParseResult(mimeType, "", 0)
+	f.addFilter("Mime", CostHead, func(ex file.FileInfoEx) (result bool, err error) {
+		var mimeResult *mimechecker.Result
+		if mimeResult, err = file.MimeResult(ex); err != nil {
 			return
 		}
-		return mimeResult == mimeType, nil
-	}, 50)
+		result = mimeResult.Matches(mimeType) &&
+			mimeResult.Confidence >= match.minConfidence &&
+			(match.ignoreParams || mimeParamsEqual(pattern.Params, mimeResult.Params))
+		return
+	})
 	return f
 }
 
+func mimeParamsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, found := b[key]; !found || !strings.EqualFold(value, other) {
+			return false
+		}
+	}
+	return true
+}
+
 // MimeRegexp adds matching against MIME type of file using regexp pattern. This is useful for finding files of given
 // type. E.g. to find all images > MimeRegexp("^image")
 func (f *Finder) MimeRegexp(pattern string) *Finder {
@@ -98,13 +292,68 @@ func (f *Finder) MimeRegexp(pattern string) *Finder {
 	if compiled, f.lastErr = regexp.Compile(pattern); f.lastErr != nil {
 		return f
 	}
-	f.addFilter(func(ex file.FileInfoEx) (result bool, err error) {
+	f.addFilter("MimeRegexp", CostHead, func(ex file.FileInfoEx) (result bool, err error) {
 		var mimeResult string
 		if mimeResult, err = ex.Mime(); err != nil {
 			return
 		}
 		return compiled.Match([]byte(mimeResult)), nil
-	}, 50)
+	})
+	return f
+}
+
+const contentMimeAttr = "mime:content"
+
+var contentMimeDetector = mimechecker.NewMulti(mimechecker.NewGoHttp(), mimechecker.NewExecutable())
+
+// ContentMime returns MIME type sniffed from content of file regardless of its extension. Result of FileInfoEx.MimeResult
+// is reused if it comes from content. Otherwise it's detected and cached on FileInfoEx.
+func ContentMime(fiex file.FileInfoEx) (result *mimechecker.Result, err error) {
+	if result, err = file.MimeResult(fiex); err != nil {
+		return
+	}
+	if result.Source == mimechecker.SourceContent {
+		return
+	}
+	for _, candidate := range result.Candidates {
+		if candidate.Source == mimechecker.SourceContent {
+			return candidate, nil
+		}
+	}
+	var v interface{}
+	if v, err = file.Attr(fiex, contentMimeAttr, func(path string) (interface{}, error) {
+		return contentMimeDetector.Detect(path)
+	}); err != nil {
+		return
+	}
+	result = v.(*mimechecker.Result)
+	return
+}
+
+// ExtensionMismatch adds matching of files which content doesn't belong to MIME family implied by their extension,
+// e.g. executable named "photo.jpg" or GIF named "song.mp3". Families are compared using mimechecker.DefaultFamilies.
+// Files with unknown extension or undetectable content aren't matched.
+func (f *Finder) ExtensionMismatch() *Finder {
+	return f.ExtensionMismatchWith(mimechecker.DefaultFamilies)
+}
+
+// ExtensionMismatchWith works like ExtensionMismatch using given families
+func (f *Finder) ExtensionMismatchWith(families mimechecker.Families) *Finder {
+	if f.lastErr != nil { return f }
+	byExtension := mimechecker.NewGoMime()
+	f.addFilter("ExtensionMismatch", CostHead, func(fiex file.FileInfoEx) (result bool, err error) {
+		extensionMime, _ := byExtension.TypeByFile(fiex.Name())
+		if extensionMime == "" {
+			return
+		}
+		var contentMime *mimechecker.Result
+		if contentMime, err = ContentMime(fiex); err != nil {
+			err = errors.Wrap(err, "ExtensionMismatch")
+			return
+		}
+		result = contentMime.MediaType != "" && !families.Equivalent(extensionMime, contentMime.MediaType)
+		return
+	})
 	return f
 }
 
@@ -115,9 +364,9 @@ func (f *Finder) RegexpName(pattern string) *Finder {
 	if compiled, f.lastErr = regexp.Compile(pattern); f.lastErr != nil {
 		return f
 	}
-	f.addFilter(func(ex file.FileInfoEx) (bool, error) {
+	f.addFilter("RegexpName", CostName, func(ex file.FileInfoEx) (bool, error) {
 		return compiled.Match([]byte(ex.Name())), nil
-	}, 2)
+	})
 	return f
 }
 
@@ -128,7 +377,7 @@ func (f *Finder) RegexpPath(pattern string) *Finder {
 	if compiled, f.lastErr = regexp.Compile(pattern); f.lastErr != nil {
 		return f
 	}
-	f.addFilter(func(ex file.FileInfoEx) (result bool, err error) {
+	f.addFilter("RegexpPath", CostName, func(ex file.FileInfoEx) (result bool, err error) {
 		var abs string
 		if abs, err = ex.Abs(); err != nil {
 			err = errors.Wrap(err, "RegexpPath")
@@ -136,6 +385,6 @@ func (f *Finder) RegexpPath(pattern string) *Finder {
 			result = compiled.Match([]byte(abs))
 		}
 		return
-	}, 2)
+	})
 	return f
 }
\ No newline at end of file
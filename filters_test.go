@@ -6,6 +6,11 @@ import (
 	"sort"
 	"github.com/duffpl/go-finder/file"
 	"fmt"
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"github.com/duffpl/go-finder/checksum"
+	"github.com/duffpl/go-finder/mimechecker"
 )
 
 func TestFinder_Size(t *testing.T) {
@@ -109,6 +114,101 @@ func TestFinder_Mime_unit(t *testing.T) {
 	}
 }
 
+func TestFinder_Mime_options(t *testing.T) {
+	mockGlob := newMockGlobFunc([]file.FileInfoEx{
+		&mockFileInfoEx{name: "image-png", mime: "image/png"},
+		&mockFileInfoEx{name: "image-gif", mime: "image/gif"},
+		&mockFileInfoEx{name: "text-plain", mime: "text/plain"},
+		&mockFileInfoEx{name: "text-utf8", mime: "text/plain; charset=UTF-8"},
+	})
+	testExpectations := []struct {
+		name string
+		mime string
+		opts []MimeOption
+		res  []string
+	}{
+		{"Exact", "text/plain", nil, []string{"text-plain"}},
+		{"Params", "text/plain; charset=utf-8", nil, []string{"text-utf8"}},
+		{"IgnoreParams", "text/plain", []MimeOption{IgnoreMimeParams()}, []string{"text-plain", "text-utf8"}},
+		{"Wildcard", "image/*", nil, []string{"image-gif", "image-png"}},
+		{"AnyType", "*/*", []MimeOption{IgnoreMimeParams()}, []string{"image-gif", "image-png", "text-plain", "text-utf8"}},
+		{"MinConfidence", "*/*", []MimeOption{MinMimeConfidence(0.8)}, nil},
+	}
+	for _, tex := range testExpectations {
+		t.Run(tex.name, func(t *testing.T) {
+			result, err := New().
+				SetGlobFunc(mockGlob).
+				Mime(tex.mime, tex.opts...).
+				Glob("test-glob")
+			if err != nil {
+				t.Error(err)
+			}
+			assert.Equal(t, tex.res, getFileNamesFromResult(result))
+		})
+	}
+}
+
+func TestFinder_ExtensionMismatch(t *testing.T) {
+	png, _ := ioutil.ReadFile("./test_files/mime/image.png")
+	dir := createTestTree(t, map[string]string{
+		"photo.jpg":  "\x7fELF\x02\x01\x01" + string(make([]byte, 57)),
+		"image.jpeg": string(png),
+	})
+	t.Run("Default", func(t *testing.T) {
+		result, err := New().ExtensionMismatch().Glob("./test_files/mime/*")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"image-gif-fake-audio.mp3"}, getFileNamesFromResult(result))
+		result, err = New().ExtensionMismatch().Glob(dir + "/*")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"photo.jpg"}, getFileNamesFromResult(result))
+	})
+	t.Run("CustomFamilies", func(t *testing.T) {
+		result, err := New().
+			ExtensionMismatchWith(mimechecker.Families{"image/png": "png", "image/jpeg": "jpeg"}).
+			Glob(dir + "/*")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"image.jpeg", "photo.jpg"}, getFileNamesFromResult(result))
+	})
+	t.Run("ReuseContentResult", func(t *testing.T) {
+		result, err := New().
+			SetGlobFunc(newMockGlobFunc([]file.FileInfoEx{
+				&mockFileInfoEx{name: "a.png", attrs: map[string]interface{}{
+					contentMimeAttr: &mimechecker.Result{MediaType: "image/gif"},
+				}},
+				&mockFileInfoEx{name: "b.png", attrs: map[string]interface{}{
+					contentMimeAttr: &mimechecker.Result{MediaType: "text/plain"},
+				}},
+				&mockFileInfoEx{name: "c", attrs: map[string]interface{}{
+					contentMimeAttr: &mimechecker.Result{MediaType: "text/plain"},
+				}},
+			})).
+			ExtensionMismatch().
+			Glob("test-glob")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"b.png"}, getFileNamesFromResult(result))
+	})
+}
+
+func TestFinder_HardLinks(t *testing.T) {
+	mockGlob := newMockGlobFunc([]file.FileInfoEx{
+		&mockFileInfoEx{name: "single", stat: &file.Stat{Ino: 1, Nlink: 1}},
+		&mockFileInfoEx{name: "linked-1", stat: &file.Stat{Ino: 2, Nlink: 3}},
+		&mockFileInfoEx{name: "linked-2", stat: &file.Stat{Ino: 2, Nlink: 3}},
+		&mockFileInfoEx{name: "no-stat"},
+	})
+	result, err := New().SetGlobFunc(mockGlob).HardLinks(MoreThan, 1).Glob("test-glob")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"linked-1", "linked-2"}, getFileNamesFromResult(result))
+	result, err = New().SetGlobFunc(mockGlob).DedupeInodes().Glob("test-glob")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"linked-1", "no-stat", "single"}, getFileNamesFromResult(result))
+	result, err = New().DedupeInodes().SetGlobFunc(mockGlob).Glob("test-glob")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"linked-1", "no-stat", "single"}, getFileNamesFromResult(result))
+	_, err = New().HardLinks("!", 1).Glob("test-glob")
+	assert.Equal(t, Errors.InvalidCmpOperator, err)
+}
+
 func TestFinder_MimeRegexp(t *testing.T) {
 	testExpectations := []struct {
 		pattern string
@@ -162,6 +262,60 @@ func TestFinder_Checksum_unit(t *testing.T) {
 	}
 }
 
+func TestFinder_ChecksumIn(t *testing.T) {
+	mockGlob := newMockGlobFunc([]file.FileInfoEx{
+		&mockFileInfoEx{name: "1", size: 10, checksum: []byte{1}},
+		&mockFileInfoEx{name: "2", size: 20, checksum: []byte{2}},
+		&mockFileInfoEx{name: "3", size: 30, checksum: []byte{3}},
+		&mockFileInfoEx{name: "3-other-size", size: 35, checksum: []byte{3}},
+	})
+	t.Run("WithoutSizes", func(t *testing.T) {
+		set, _ := checksum.NewSet("01", "03")
+		result, err := New().
+			SetGlobFunc(mockGlob).
+			ChecksumIn(set).
+			Glob("*")
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(t, []string{"1", "3", "3-other-size"}, getFileNamesFromResult(result))
+	})
+	t.Run("WithSizes", func(t *testing.T) {
+		set, _ := checksum.NewSetWithSizes(map[string]int64{"01": 10, "03": 30})
+		result, err := New().
+			SetGlobFunc(mockGlob).
+			ChecksumIn(set).
+			Glob("*")
+		if err != nil {
+			t.Error(err)
+		}
+		assert.Equal(t, []string{"1", "3"}, getFileNamesFromResult(result))
+	})
+	t.Run("SHA256", func(t *testing.T) {
+		set, _ := checksum.NewSet("87428fc522803d31065e7bce3cf03fe475096631e5e07bbd7a0fde60c4cf25c7")
+		result, err := New().ChecksumIn(set).Glob("./test_files/checksum/*")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"60b725f10c9c85c70d97880dfe8191b3"}, getFileNamesFromResult(result))
+	})
+	t.Run("InvalidSets", func(t *testing.T) {
+		assert.Error(t, New().ChecksumIn(nil).lastErr)
+		mixed, _ := checksum.NewSet("01", "0102")
+		assert.Error(t, New().ChecksumIn(mixed).lastErr)
+	})
+	t.Run("EmptySet", func(t *testing.T) {
+		set, _ := checksum.NewSet()
+		item := &mockFileInfoEx{name: "1", size: 10, checksum: []byte{1}}
+		result, err := New().
+			SetGlobFunc(newMockGlobFunc([]file.FileInfoEx{item})).
+			CollectStats().
+			ChecksumIn(set).
+			Glob("*")
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+		assert.False(t, item.hashed)
+	})
+}
+
 func TestFinder_Checksum_integration(t *testing.T) {
 	testExpectations := []struct {
 		checksum string
@@ -181,6 +335,33 @@ func TestFinder_Checksum_integration(t *testing.T) {
 	}
 }
 
+func TestFinder_SimilarTo(t *testing.T) {
+	original := bytes.Repeat([]byte("The quick brown fox jumps over the lazy dog. "), 100)
+	for i := range original {
+		original[i] += byte(i % 7)
+	}
+	edited := append([]byte("new header\n"), original...)
+	unrelated := bytes.Repeat([]byte("Pack my box with five dozen liquor jugs! "), 110)
+	dir := createTestTree(t, map[string]string{"original": string(original), "edited": string(edited), "unrelated": string(unrelated)})
+	reference := filepath.Join(dir, "original")
+	result, err := New().
+		SimilarTo(reference, 50).
+		Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"edited", "original"}, getFileNamesFromResult(result))
+	for _, item := range result {
+		score, ok := SimilarityScore(item, reference)
+		assert.True(t, ok)
+		assert.True(t, score >= 50, "score: %d", score)
+	}
+	t.Run("MissingReference", func(t *testing.T) {
+		_, err := New().SimilarTo(filepath.Join(dir, "not-there"), 50).Glob(filepath.Join(dir, "*"))
+		assert.Error(t, err)
+	})
+}
+
 func TestFinder_RegexpName(t *testing.T) {
 	testExpectations := []struct {
 		pattern string
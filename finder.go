@@ -3,9 +3,10 @@ package finder
 import (
 	"github.com/pkg/errors"
 	"github.com/duffpl/go-finder/mimechecker"
-	"sort"
 	"github.com/bmatcuk/doublestar"
 	"sync"
+	"sync/atomic"
+	"time"
 	"github.com/duffpl/go-finder/checksum"
 	"github.com/duffpl/go-finder/file"
 )
@@ -13,10 +14,15 @@ import (
 type Finder struct {
 	numCheckers int
 	globFunc    FileInfoExGlobFunc
-	filters []struct {
-		callback func(ex file.FileInfoEx) (bool, error)
-		order    int
-	}
+	dedupeInodes bool
+	filters     []Filter
+	adaptive    bool
+	stats       bool
+	statsHook   func(stats *Stats)
+	plan        *planner
+	progressInterval time.Duration
+	progressCb       func(progress Progress)
+	planMu      sync.Mutex
 	lastErr error
 }
 
@@ -27,7 +33,7 @@ var (
 
 func init() {
 	mc := mimechecker.NewMulti(mimechecker.NewGoHttp(), mimechecker.NewGoMime())
-	defaultFileInfoExGlob = NewLazyGlobber(doublestar.Glob, checksum.MD5ByPath, mc.TypeByFile)
+	defaultFileInfoExGlob = NewLazyCountingGlobber(doublestar.Glob, checksum.MD5CountingByPath, mc)
 }
 
 func New() *Finder {
@@ -44,6 +50,21 @@ func (f *Finder) SetGlobFunc(gf FileInfoExGlobFunc) *Finder {
 	return f
 }
 
+// DedupeInodes makes Glob return only first path of every inode, so hard linked files are reported (and hashed) once.
+// It applies to glob function set before or after it.
+func (f *Finder) DedupeInodes() *Finder {
+	f.dedupeInodes = true
+	return f
+}
+
+// glob lists items using glob function, deduplicated by inode if DedupeInodes was enabled
+func (f *Finder) glob(pattern string) ([]file.FileInfoEx, error) {
+	if f.dedupeInodes {
+		return DedupeInodes(f.globFunc)(pattern)
+	}
+	return f.globFunc(pattern)
+}
+
 // SetCheckerConcurrency sets number of go routines that are used for checking filters. Default is 8. Usually I/O will
 // be bottleneck.
 func (f *Finder) SetCheckerConcurrency(num int) *Finder {
@@ -57,72 +78,83 @@ func (f *Finder) SetCheckerConcurrency(num int) *Finder {
 
 // Glob is used for fetching result list of files as slice of file.FileInfoEx items
 func (f *Finder) Glob(pattern string) (result []file.FileInfoEx, err error) {
+	var resultMu sync.Mutex
+	err = f.GlobEach(pattern, func(info file.FileInfoEx) {
+		resultMu.Lock()
+		result = append(result, info)
+		resultMu.Unlock()
+	})
+	return
+}
+
+// GlobEach streams matching items to cb as soon as their filters are checked. cb is called concurrently from checker
+// go routines, GlobEach returns after all calls are done.
+func (f *Finder) GlobEach(pattern string, cb func(info file.FileInfoEx)) (err error) {
 	if f.lastErr != nil {
 		err = f.lastErr
 		return
 	}
 	var globResult []file.FileInfoEx
-	if globResult, err = f.globFunc(pattern); err != nil {
+	if globResult, err = f.glob(pattern); err != nil {
 		err = errors.Wrap(err, "glob")
 		return
 	}
-	tasksWg := &sync.WaitGroup{}
-	tasksWg.Add(len(globResult))
-	filtersOutput := f.runFilters(f.numCheckers, tasksWg, globResult)
-	createFiltersOutputConsumer(filtersOutput, &result, tasksWg)
-	tasksWg.Wait()
+	check := f.checkFilters
+	var plan *planner
+	if f.adaptive || f.stats {
+		plan = newPlanner(f.filters, f.adaptive)
+		plan.listed = int64(len(globResult))
+		f.planMu.Lock()
+		f.plan = plan
+		f.planMu.Unlock()
+		check = plan.check
+	}
+	if f.progressCb != nil {
+		progress := startProgress(len(globResult), f.progressInterval, f.progressCb)
+		defer progress.finish()
+		check = progress.track(check)
+	}
+	start := time.Now()
+	runParallel(f.numCheckers, len(globResult), func(idx int) {
+		if check(globResult[idx]) {
+			cb(globResult[idx])
+		}
+	})
+	if plan != nil {
+		atomic.StoreInt64(&plan.duration, int64(time.Since(start)))
+		if f.statsHook != nil {
+			f.statsHook(plan.snapshot())
+		}
+	}
 	return
 }
 
-func (f *Finder) runFilters(workerCnt int, tasksWg *sync.WaitGroup, entries []file.FileInfoEx) (chan file.FileInfoEx) {
-	output := make(chan file.FileInfoEx)
-	in := make(chan file.FileInfoEx)
-	for i:=0;i<workerCnt;i++ {
+// runParallel calls cb for every index in [0, n) using workerCnt go routines and waits until all calls are done
+func runParallel(workerCnt, n int, cb func(idx int)) {
+	in := make(chan int)
+	workersWg := &sync.WaitGroup{}
+	workersWg.Add(workerCnt)
+	for i := 0; i < workerCnt; i++ {
 		go func() {
-			for info := range in {
-				if f.checkFilters(info) {
-					output <- info
-				} else {
-					tasksWg.Done()
-				}
+			defer workersWg.Done()
+			for idx := range in {
+				cb(idx)
 			}
 		}()
 	}
-	go func() {
-		for _, entry := range entries {
-			in <- entry
-		}
-		close(in)
-	}()
-	return output
-}
-
-
-func createFiltersOutputConsumer(in chan file.FileInfoEx, result *[]file.FileInfoEx, tasksWg *sync.WaitGroup) {
-	go func() {
-		for info := range in {
-			*result = append(*result, info)
-			tasksWg.Done()
-		}
-	}()
+	for idx := 0; idx < n; idx++ {
+		in <- idx
+	}
+	close(in)
+	workersWg.Wait()
 }
 
 func (f *Finder) checkFilters(input file.FileInfoEx) bool {
 	for _, filter := range f.filters {
-		matched, _ := filter.callback(input)
+		matched, _ := filter.Match(input)
 		if !matched {
 			return false
 		}
 	}
 	return true
 }
-
-func (f *Finder) addFilter(callback func(fiex file.FileInfoEx) (bool, error), order int) {
-	f.filters = append(f.filters, struct {
-		callback func(ex file.FileInfoEx) (bool, error)
-		order    int
-	}{callback, order})
-	sort.Slice(f.filters, func(i, j int) bool {
-		return f.filters[i].order < f.filters[j].order
-	})
-}
@@ -0,0 +1,61 @@
+package finder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// filterExpectation is case of table test: names of files matched by finder
+type filterExpectation struct {
+	name   string
+	finder *Finder
+	result []string
+}
+
+// runFilterExpectations checks every expectation against items matched by pattern. Default glob function of finder is
+// replaced with glob unless it's nil.
+func runFilterExpectations(t *testing.T, glob FileInfoExGlobFunc, pattern string, expectations []filterExpectation) {
+	for _, expectation := range expectations {
+		t.Run(expectation.name, func(t *testing.T) {
+			if glob != nil {
+				expectation.finder.SetGlobFunc(glob)
+			}
+			result, err := expectation.finder.Glob(pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expectation.result, getFileNamesFromResult(result))
+		})
+	}
+}
+
+// withAttr creates mock item which attribute is already computed
+func withAttr(name, attr string, value interface{}) *mockFileInfoEx {
+	return &mockFileInfoEx{name: name, attrs: map[string]interface{}{attr: value}}
+}
+
+// createTestTree creates temporary dir with files given by paths relative to it. Dir is removed when test finishes.
+func createTestTree(t *testing.T, files map[string]string) (dir string) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		writeTestFile(t, filepath.Join(dir, name), content)
+	}
+	return
+}
+
+// writeTestFile writes content to path creating missing parent dirs
+func writeTestFile(t *testing.T, path, content string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -3,23 +3,57 @@ package finder
 import (
 	"github.com/pkg/errors"
 	"github.com/duffpl/go-finder/file"
+	"github.com/duffpl/go-finder/mimechecker"
 )
 
 type GlobFunc func(pattern string) ([]string, error)
 type FileInfoExGlobFunc func(pattern string) ([]file.FileInfoEx, error)
 
 // NewLazyGlobber creates function that uses result of fileinfo.Glob to create slice of file.FileInfoEx items with
-// injected checksum and mime callbacks
+// injected checksum and mime callbacks. Checksums are cached per inode within single glob call, so hard links are
+// hashed once.
 func NewLazyGlobber(gf GlobFunc, csCb file.ChecksumCallback, mCb file.MimeCallback) FileInfoExGlobFunc {
+	return newLazyGlobber(gf, file.CountingChecksum(csCb), func(path string, csCb file.CountingChecksumCallback) (file.FileInfoEx, error) {
+		var plainCsCb file.ChecksumCallback
+		if csCb != nil {
+			plainCsCb = func(path string) ([]byte, error) {
+				return csCb(path, nil)
+			}
+		}
+		return file.NewLazyFileInfoExByPath(path, plainCsCb, mCb)
+	})
+}
+
+// NewLazyDetectingGlobber works like NewLazyGlobber but MIME type is detected with detector so items report
+// detection details with MimeResult
+func NewLazyDetectingGlobber(gf GlobFunc, csCb file.ChecksumCallback, detector mimechecker.Detector) FileInfoExGlobFunc {
+	return NewLazyCountingGlobber(gf, file.CountingChecksum(csCb), detector)
+}
+
+// NewLazyCountingGlobber works like NewLazyDetectingGlobber but checksum callback reports bytes as they are read, so
+// stats and progress of Finder count only bytes actually hashed
+func NewLazyCountingGlobber(gf GlobFunc, csCb file.CountingChecksumCallback, detector mimechecker.Detector) FileInfoExGlobFunc {
+	return newLazyGlobber(gf, csCb, func(path string, csCb file.CountingChecksumCallback) (file.FileInfoEx, error) {
+		return file.NewLazyFileInfoExCounting(path, csCb, detector.Detect)
+	})
+}
+
+type newFileInfoExFunc func(path string, csCb file.CountingChecksumCallback) (file.FileInfoEx, error)
+
+func newLazyGlobber(gf GlobFunc, csCb file.CountingChecksumCallback, newInfo newFileInfoExFunc) FileInfoExGlobFunc {
 	return func(pattern string) (result []file.FileInfoEx, err error) {
 		var matches []string
 		if matches, err = gf(pattern); err != nil {
 			err = errors.Wrap(err, "glob")
 			return
 		}
+		globCsCb := csCb
+		if globCsCb != nil {
+			globCsCb = file.PerInodeCountingChecksum(csCb)
+		}
 		var info file.FileInfoEx
 		for _, match := range matches {
-			if info, err = file.NewLazyFileInfoExByPath(match, csCb, mCb); err != nil {
+			if info, err = newInfo(match, globCsCb); err != nil {
 				err = errors.Wrap(err, "new fileinfoex")
 				return
 			}
@@ -27,4 +61,27 @@ func NewLazyGlobber(gf GlobFunc, csCb file.ChecksumCallback, mCb file.MimeCallba
 		}
 		return
 	}
-}
\ No newline at end of file
+}
+
+// DedupeInodes wraps glob function so only first path of every inode is returned. Later hard links (and paths
+// matched more than once) are dropped. Items without inode information are always kept.
+func DedupeInodes(gf FileInfoExGlobFunc) FileInfoExGlobFunc {
+	return func(pattern string) (result []file.FileInfoEx, err error) {
+		var globResult []file.FileInfoEx
+		if globResult, err = gf(pattern); err != nil {
+			return
+		}
+		seen := map[[2]uint64]struct{}{}
+		for _, info := range globResult {
+			if stat, ok := file.StatOf(info); ok {
+				key := [2]uint64{stat.Dev, stat.Ino}
+				if _, found := seen[key]; found {
+					continue
+				}
+				seen[key] = struct{}{}
+			}
+			result = append(result, info)
+		}
+		return
+	}
+}
@@ -0,0 +1,140 @@
+package finder
+
+import (
+	"path/filepath"
+	"regexp"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/duffpl/go-finder/gosource"
+)
+
+const (
+	goHeaderAttr = "go-source:header"
+	goSourceAttr = "go-source"
+)
+
+// GoSource returns whole Go source file parsed with declarations. Once it's parsed GoSourceHeader reuses it.
+func GoSource(fiex file.FileInfoEx) (source *gosource.File, err error) {
+	return goSourceAttrValue(fiex, goSourceAttr, gosource.Read)
+}
+
+// GoSourceHeader returns package, imports and build constraint of Go source file. Only part of file up to imports is
+// parsed unless GoSource has been already computed.
+func GoSourceHeader(fiex file.FileInfoEx) (source *gosource.File, err error) {
+	if cached, _ := file.Attr(fiex, goSourceAttr, nil); cached != nil {
+		return cached.(*gosource.File), nil
+	}
+	return goSourceAttrValue(fiex, goHeaderAttr, gosource.ReadHeader)
+}
+
+func goSourceAttrValue(fiex file.FileInfoEx, attr string, read func(path string) (*gosource.File, error)) (source *gosource.File, err error) {
+	var v interface{}
+	if v, err = file.Attr(fiex, attr, func(path string) (interface{}, error) {
+		return read(path)
+	}); err != nil {
+		return
+	}
+	source = v.(*gosource.File)
+	return
+}
+
+// addGoSourceFilter adds filter that is checked only for *.go files, others are rejected without being read. Filters
+// run after name filters so narrowing with RegexpName limits parsed files even further.
+func (f *Finder) addGoSourceFilter(name string, header bool, cb func(source *gosource.File) bool) {
+	cost := CostContent
+	if header {
+		cost = CostHead
+	}
+	f.addInfoFilter(name, cost, func(fiex file.FileInfoEx) (interface{}, error) {
+		switch {
+		case filepath.Ext(fiex.Name()) != ".go":
+			return nil, nil
+		case header:
+			return GoSourceHeader(fiex)
+		}
+		return GoSource(fiex)
+	}, func(source interface{}) bool {
+		parsed, ok := source.(*gosource.File)
+		return ok && cb(parsed)
+	})
+}
+
+// GoPackage adds matching of Go source files declaring given package name
+func (f *Finder) GoPackage(name string) *Finder {
+	if f.lastErr != nil { return f }
+	f.addGoSourceFilter("GoPackage", true, func(source *gosource.File) bool {
+		return source.Package == name
+	})
+	return f
+}
+
+// GoImports adds matching of Go source files importing given package path
+func (f *Finder) GoImports(importPath string) *Finder {
+	if f.lastErr != nil { return f }
+	f.addGoSourceFilter("GoImports", true, func(source *gosource.File) bool {
+		for _, imported := range source.Imports {
+			if imported == importPath {
+				return true
+			}
+		}
+		return false
+	})
+	return f
+}
+
+// GoBuildTag adds matching of Go source files which build constraint mentions tag, e.g. GoBuildTag("linux") matches
+// files with "//go:build linux" as well as "//go:build !linux"
+func (f *Finder) GoBuildTag(tag string) *Finder {
+	if f.lastErr != nil { return f }
+	f.addGoSourceFilter("GoBuildTag", true, func(source *gosource.File) bool {
+		return source.HasConstraintTag(tag)
+	})
+	return f
+}
+
+// GoBuildsWith adds matching of Go source files which build constraint is satisfied by given tags. Files without
+// constraint always match. File name suffixes like _linux.go aren't taken into account.
+func (f *Finder) GoBuildsWith(tags ...string) *Finder {
+	if f.lastErr != nil { return f }
+	f.addGoSourceFilter("GoBuildsWith", true, func(source *gosource.File) bool {
+		return source.Satisfied(tags...)
+	})
+	return f
+}
+
+// GoFunc adds matching of Go source files declaring top level function which name matches regexp pattern. Methods are
+// matched as "Recv.Name". E.g. to find benchmarks > RegexpName(`_test\.go$`).GoFunc(`^Benchmark`)
+func (f *Finder) GoFunc(pattern string) *Finder {
+	if f.lastErr != nil { return f }
+	var compiled *regexp.Regexp
+	if compiled, f.lastErr = regexp.Compile(pattern); f.lastErr != nil {
+		return f
+	}
+	f.addGoSourceFilter("GoFunc", false, func(source *gosource.File) bool {
+		return anyMatches(compiled, source.Funcs)
+	})
+	return f
+}
+
+// GoExported adds matching of Go source files declaring exported top level identifier which name matches regexp
+// pattern
+func (f *Finder) GoExported(pattern string) *Finder {
+	if f.lastErr != nil { return f }
+	var compiled *regexp.Regexp
+	if compiled, f.lastErr = regexp.Compile(pattern); f.lastErr != nil {
+		return f
+	}
+	f.addGoSourceFilter("GoExported", false, func(source *gosource.File) bool {
+		return anyMatches(compiled, source.Exported)
+	})
+	return f
+}
+
+func anyMatches(compiled *regexp.Regexp, values []string) bool {
+	for _, value := range values {
+		if compiled.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
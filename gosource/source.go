@@ -0,0 +1,172 @@
+package gosource
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// File holds facts about Go source file. Header fields are filled by both ReadHeader and Read, declarations only by
+// Read.
+type File struct {
+	Package string
+	Imports []string
+	// Constraint is parsed //go:build (or legacy // +build) expression, nil if file has none
+	Constraint constraint.Expr
+	// Test is true for files named *_test.go
+	Test bool
+
+	// Funcs are names of top level functions. Methods are named "Recv.Name"
+	Funcs []string
+	// Exported are exported top level identifiers: functions, types, variables and constants. Methods are skipped
+	Exported []string
+}
+
+// ReadHeader parses file up to import declarations
+func ReadHeader(path string) (result *File, err error) {
+	return parse(path, parser.ImportsOnly|parser.ParseComments)
+}
+
+// Read parses whole file
+func Read(path string) (result *File, err error) {
+	return parse(path, parser.ParseComments|parser.SkipObjectResolution)
+}
+
+func parse(path string, mode parser.Mode) (result *File, err error) {
+	fset := token.NewFileSet()
+	var parsed *ast.File
+	if parsed, err = parser.ParseFile(fset, path, nil, mode); err != nil {
+		err = errors.Wrap(err, "go/parser")
+		return
+	}
+	result = &File{
+		Package: parsed.Name.Name,
+		Test:    strings.HasSuffix(filepath.Base(path), "_test.go"),
+	}
+	for _, spec := range parsed.Imports {
+		var importPath string
+		if importPath, err = strconv.Unquote(spec.Path.Value); err != nil {
+			err = errors.Wrap(err, "import path")
+			return
+		}
+		result.Imports = append(result.Imports, importPath)
+	}
+	if result.Constraint, err = buildConstraint(parsed); err != nil {
+		return
+	}
+	if mode&parser.ImportsOnly == 0 {
+		readDecls(parsed, result)
+	}
+	return
+}
+
+// buildConstraint reads constraint from comments above package clause. //go:build line takes precedence, multiple
+// // +build lines are joined with AND.
+func buildConstraint(parsed *ast.File) (expr constraint.Expr, err error) {
+	var plusBuild constraint.Expr
+	for _, group := range parsed.Comments {
+		if group.Pos() >= parsed.Package {
+			break
+		}
+		for _, comment := range group.List {
+			switch {
+			case constraint.IsGoBuild(comment.Text):
+				if expr, err = constraint.Parse(comment.Text); err != nil {
+					err = errors.Wrap(err, "go:build")
+				}
+				return
+			case constraint.IsPlusBuild(comment.Text):
+				var line constraint.Expr
+				if line, err = constraint.Parse(comment.Text); err != nil {
+					err = errors.Wrap(err, "+build")
+					return
+				}
+				if plusBuild == nil {
+					plusBuild = line
+				} else {
+					plusBuild = &constraint.AndExpr{X: plusBuild, Y: line}
+				}
+			}
+		}
+	}
+	return plusBuild, nil
+}
+
+func readDecls(parsed *ast.File, result *File) {
+	for _, decl := range parsed.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				result.Funcs = append(result.Funcs, decl.Name.Name)
+				if decl.Name.IsExported() {
+					result.Exported = append(result.Exported, decl.Name.Name)
+				}
+			} else if len(decl.Recv.List) > 0 {
+				result.Funcs = append(result.Funcs, receiverName(decl.Recv.List[0].Type)+"."+decl.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if spec.Name.IsExported() {
+						result.Exported = append(result.Exported, spec.Name.Name)
+					}
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						if name.IsExported() {
+							result.Exported = append(result.Exported, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func receiverName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(expr.X)
+	case *ast.IndexExpr:
+		return receiverName(expr.X)
+	case *ast.IndexListExpr:
+		return receiverName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+// Satisfied checks if file's build constraint is satisfied when given tags (GOOS, GOARCH, custom tags) are set.
+// Files without constraint are always satisfied.
+func (f *File) Satisfied(tags ...string) bool {
+	if f.Constraint == nil {
+		return true
+	}
+	return f.Constraint.Eval(func(tag string) bool {
+		for _, t := range tags {
+			if t == tag {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// HasConstraintTag checks if tag is mentioned in file's build constraint, negated or not
+func (f *File) HasConstraintTag(tag string) bool {
+	found := false
+	if f.Constraint != nil {
+		f.Constraint.Eval(func(t string) bool {
+			found = found || t == tag
+			return false
+		})
+	}
+	return found
+}
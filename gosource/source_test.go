@@ -0,0 +1,112 @@
+package gosource
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSource = `//go:build linux && !arm
+
+// Package sample is used in tests
+package sample
+
+import (
+	"fmt"
+	nethttp "net/http"
+)
+
+const Version = "1"
+
+var internal, Exported = 1, 2
+
+type Server struct{}
+
+type list[T any] []T
+
+func (s *Server) Serve() {}
+
+func (l list[T]) Len() int { return len(l) }
+
+func New() *Server { return &Server{} }
+
+func helper() { fmt.Println(nethttp.StatusOK) }
+`
+
+const legacySource = `// +build linux darwin
+// +build amd64
+
+package sample
+`
+
+func writeSource(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gosource")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := writeSource(t, dir, "sample_test.go", testSource)
+	t.Run("Header", func(t *testing.T) {
+		source, err := ReadHeader(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "sample", source.Package)
+		assert.Equal(t, []string{"fmt", "net/http"}, source.Imports)
+		assert.Equal(t, "linux && !arm", source.Constraint.String())
+		assert.True(t, source.Test)
+		assert.Nil(t, source.Funcs)
+	})
+	t.Run("QuotedImports", func(t *testing.T) {
+		quoted := writeSource(t, dir, "quoted.go", "package sample\n\nimport (\n\t\"ne\\x74/url\"\n\t`os`\n)\n")
+		source, err := ReadHeader(quoted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []string{"net/url", "os"}, source.Imports)
+	})
+	t.Run("Full", func(t *testing.T) {
+		source, err := Read(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []string{"Server.Serve", "list.Len", "New", "helper"}, source.Funcs)
+		assert.Equal(t, []string{"Version", "Exported", "Server", "New"}, source.Exported)
+	})
+	t.Run("Constraint", func(t *testing.T) {
+		source, _ := ReadHeader(path)
+		assert.True(t, source.Satisfied("linux", "amd64"))
+		assert.False(t, source.Satisfied("linux", "arm"))
+		assert.True(t, source.HasConstraintTag("arm"))
+		assert.False(t, source.HasConstraintTag("windows"))
+	})
+	t.Run("LegacyConstraint", func(t *testing.T) {
+		source, err := ReadHeader(writeSource(t, dir, "legacy.go", legacySource))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.False(t, source.Test)
+		assert.True(t, source.Satisfied("darwin", "amd64"))
+		assert.False(t, source.Satisfied("darwin", "arm64"))
+	})
+	t.Run("NoConstraint", func(t *testing.T) {
+		source, _ := ReadHeader(writeSource(t, dir, "plain.go", "package plain\n"))
+		assert.Nil(t, source.Constraint)
+		assert.True(t, source.Satisfied())
+	})
+	t.Run("SyntaxError", func(t *testing.T) {
+		_, err := ReadHeader(writeSource(t, dir, "broken.go", "packag broken\n"))
+		assert.Error(t, err)
+	})
+}
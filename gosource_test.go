@@ -0,0 +1,39 @@
+package finder
+
+import (
+	"testing"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinder_GoSourceFilters(t *testing.T) {
+	dir := createTestTree(t, map[string]string{
+		"main.go":       "package main\n\nimport \"net/http\"\n\nfunc main() { http.ListenAndServe(\"\", nil) }\n",
+		"main_linux.go": "//go:build linux\n\npackage main\n\nimport \"os\"\n\nvar Name = os.Args[0]\n",
+		"lib.go":        "//go:build !linux\n\npackage lib\n\ntype Client struct{}\n",
+		"lib_test.go":   "package lib\n\nimport \"testing\"\n\nfunc BenchmarkClient(b *testing.B) {}\n",
+		"not-go.txt":    "package main\n",
+		"broken.go":     "packag main\n",
+	})
+	runFilterExpectations(t, nil, dir + "/*", []filterExpectation{
+		{"GoPackage", New().GoPackage("main"), []string{"main.go", "main_linux.go"}},
+		{"GoImports", New().GoImports("net/http"), []string{"main.go"}},
+		{"GoBuildTag", New().GoBuildTag("linux"), []string{"lib.go", "main_linux.go"}},
+		{"GoBuildsWith", New().GoBuildsWith("darwin", "amd64"), []string{"lib.go", "lib_test.go", "main.go"}},
+		{"GoFunc", New().RegexpName(`_test\.go$`).GoFunc("^Benchmark"), []string{"lib_test.go"}},
+		{"GoExported", New().GoExported("^(Client|Name)$"), []string{"lib.go", "main_linux.go"}},
+	})
+}
+
+func TestGoSourceHeader_reusesFullParse(t *testing.T) {
+	result, err := New().GoFunc("^TestGoSourceHeader_reusesFullParse$").Glob("./gosource_test.go")
+	if err != nil || len(result) != 1 {
+		t.Fatal("expected test file to be matched", err)
+	}
+	header, err := GoSourceHeader(result[0])
+	assert.NoError(t, err)
+	assert.NotNil(t, header.Funcs)
+	cached, _ := file.Attr(result[0], goHeaderAttr, nil)
+	assert.Nil(t, cached)
+}
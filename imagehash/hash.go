@@ -0,0 +1,158 @@
+package imagehash
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"math/bits"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Hash is 64 bit perceptual hash of image. Similar images have hashes with small Hamming distance.
+type Hash uint64
+
+// Kind is string const enum for hashing algorithms
+type Kind string
+
+const (
+	// Average compares each pixel of 8x8 grayscale thumbnail with mean brightness
+	Average Kind = "average"
+	// Difference compares brightness of neighbouring pixels of 9x8 grayscale thumbnail
+	Difference Kind = "difference"
+	// Perceptual compares low frequency DCT coefficients of 32x32 grayscale thumbnail with their median. It's the most
+	// robust against rescaling and recompression.
+	Perceptual Kind = "perceptual"
+)
+
+// Distance returns Hamming distance between two hashes
+func Distance(a, b Hash) int {
+	return bits.OnesCount64(uint64(a ^ b))
+}
+
+func (h Hash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+// ByPath decodes image under path (PNG, JPEG or GIF) and computes its hash
+func ByPath(kind Kind, path string) (result Hash, err error) {
+	var handle *os.File
+	if handle, err = os.Open(path); err != nil {
+		err = errors.Wrap(err, "os.Open")
+		return
+	}
+	defer handle.Close()
+	var img image.Image
+	if img, _, err = image.Decode(handle); err != nil {
+		err = errors.Wrap(err, "image.Decode")
+		return
+	}
+	return Compute(kind, img)
+}
+
+// Compute computes hash of decoded image
+func Compute(kind Kind, img image.Image) (result Hash, err error) {
+	switch kind {
+	case Average:
+		result = averageHash(img)
+	case Difference:
+		result = differenceHash(img)
+	case Perceptual:
+		result = perceptualHash(img)
+	default:
+		err = fmt.Errorf("unknown hash kind: %s", kind)
+	}
+	return
+}
+
+func averageHash(img image.Image) (result Hash) {
+	pixels := grayscale(img, 8, 8)
+	mean := 0.0
+	for _, v := range pixels {
+		mean += v
+	}
+	mean /= float64(len(pixels))
+	for i, v := range pixels {
+		if v > mean {
+			result |= 1 << uint(i)
+		}
+	}
+	return
+}
+
+func differenceHash(img image.Image) (result Hash) {
+	pixels := grayscale(img, 9, 8)
+	bit := uint(0)
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if pixels[y*9+x] < pixels[y*9+x+1] {
+				result |= 1 << bit
+			}
+			bit++
+		}
+	}
+	return
+}
+
+func perceptualHash(img image.Image) (result Hash) {
+	const size = 32
+	pixels := grayscale(img, size, size)
+	coefficients := make([]float64, 0, 64)
+	for v := 0; v < 8; v++ {
+		for u := 0; u < 8; u++ {
+			sum := 0.0
+			for y := 0; y < size; y++ {
+				for x := 0; x < size; x++ {
+					sum += pixels[y*size+x] *
+						math.Cos(float64(2*x+1)*float64(u)*math.Pi/(2*size)) *
+						math.Cos(float64(2*y+1)*float64(v)*math.Pi/(2*size))
+				}
+			}
+			coefficients = append(coefficients, sum)
+		}
+	}
+	// DC coefficient holds average brightness only, it would dominate median
+	sorted := append([]float64(nil), coefficients[1:]...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	for i, c := range coefficients {
+		if c > median {
+			result |= 1 << uint(i)
+		}
+	}
+	return
+}
+
+// grayscale scales image to w x h using box filter and returns luminance values row by row
+func grayscale(img image.Image, w, h int) []float64 {
+	bounds := img.Bounds()
+	result := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/h
+		y1 := bounds.Min.Y + (y+1)*bounds.Dy()/h
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < w; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/w
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/w
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			sum := 0.0
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					r, g, b, _ := img.At(sx, sy).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+				}
+			}
+			result[y*w+x] = sum / float64((y1-y0)*(x1-x0))
+		}
+	}
+	return result
+}
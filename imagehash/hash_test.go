@@ -0,0 +1,52 @@
+package imagehash
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	"github.com/duffpl/go-finder/internal/testimage"
+	"github.com/stretchr/testify/assert"
+)
+
+func recompressed(t *testing.T, img image.Image) image.Image {
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: 40}); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := jpeg.Decode(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestCompute(t *testing.T) {
+	original := testimage.Pattern(128, false)
+	resized := recompressed(t, testimage.Pattern(48, false))
+	different := testimage.Pattern(128, true)
+	for _, kind := range []Kind{Average, Difference, Perceptual} {
+		t.Run(string(kind), func(t *testing.T) {
+			originalHash, err := Compute(kind, original)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resizedHash, _ := Compute(kind, resized)
+			differentHash, _ := Compute(kind, different)
+			assert.True(t, Distance(originalHash, resizedHash) <= 10, "resized distance: %d", Distance(originalHash, resizedHash))
+			assert.True(t, Distance(originalHash, differentHash) > 20, "different distance: %d", Distance(originalHash, differentHash))
+		})
+	}
+	t.Run("UnknownKind", func(t *testing.T) {
+		_, err := Compute("unknown", original)
+		assert.Error(t, err)
+	})
+}
+
+func TestByPath(t *testing.T) {
+	_, err := ByPath(Perceptual, "../test_files/mime/image.png")
+	assert.NoError(t, err)
+	_, err = ByPath(Perceptual, "../test_files/mime/text.txt")
+	assert.Error(t, err)
+}
@@ -0,0 +1,226 @@
+package imagemeta
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	tagMake             = 0x010f
+	tagModel            = 0x0110
+	tagOrientation      = 0x0112
+	tagDateTime         = 0x0132
+	tagExifIFD          = 0x8769
+	tagGPSIFD           = 0x8825
+	tagDateTimeOriginal = 0x9003
+	tagGPSLatitude      = 0x0002
+
+	exifTypeASCII = 2
+	exifTypeShort = 3
+	exifTypeLong  = 4
+
+	exifDateLayout = "2006:01:02 15:04:05"
+)
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// findJpegExif walks JPEG markers until start of scan looking for APP1 segment with EXIF data
+func findJpegExif(r *bufio.Reader) (exif []byte, err error) {
+	soi := make([]byte, 2)
+	if _, err = io.ReadFull(r, soi); err != nil || soi[0] != 0xff || soi[1] != 0xd8 {
+		return nil, errors.New("not a jpeg")
+	}
+	header := make([]byte, 4)
+	for {
+		if _, err = io.ReadFull(r, header); err != nil {
+			return nil, nil
+		}
+		if header[0] != 0xff {
+			return nil, errors.New("invalid jpeg marker")
+		}
+		marker := header[1]
+		length := int(binary.BigEndian.Uint16(header[2:])) - 2
+		if marker == 0xda || marker == 0xd9 || length < 0 {
+			return nil, nil
+		}
+		if marker != 0xe1 {
+			if _, err = r.Discard(length); err != nil {
+				return nil, nil
+			}
+			continue
+		}
+		segment := make([]byte, length)
+		if _, err = io.ReadFull(r, segment); err != nil {
+			return nil, nil
+		}
+		if bytes.HasPrefix(segment, exifHeader) {
+			return segment[len(exifHeader):], nil
+		}
+	}
+}
+
+// findPngExif walks PNG chunks until image data looking for eXIf chunk. Chunks claiming to be longer than rest of file
+// (size bytes) end the walk.
+func findPngExif(r *bufio.Reader, size int64) (exif []byte, err error) {
+	if _, err = r.Discard(8); err != nil {
+		return nil, nil
+	}
+	size -= 8
+	header := make([]byte, 8)
+	for {
+		if _, err = io.ReadFull(r, header); err != nil {
+			return nil, nil
+		}
+		length := int64(binary.BigEndian.Uint32(header))
+		chunkType := string(header[4:])
+		if size -= 8; length+4 > size {
+			return nil, nil
+		}
+		size -= length + 4
+		if chunkType == "IDAT" || chunkType == "IEND" {
+			return nil, nil
+		}
+		if chunkType != "eXIf" {
+			if _, err = r.Discard(int(length + 4)); err != nil {
+				return nil, nil
+			}
+			continue
+		}
+		exif = make([]byte, length)
+		if _, err = io.ReadFull(r, exif); err != nil {
+			return nil, nil
+		}
+		return exif, nil
+	}
+}
+
+type exifEntry struct {
+	tag, kind uint16
+	count     uint32
+	value     []byte
+}
+
+type exifReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// parseExif parses TIFF structure of EXIF data and fills EXIF fields of info
+func parseExif(data []byte, info *Info) (err error) {
+	r := &exifReader{data: data}
+	if len(data) < 8 {
+		return errors.New("exif too short")
+	}
+	switch string(data[:2]) {
+	case "II":
+		r.order = binary.LittleEndian
+	case "MM":
+		r.order = binary.BigEndian
+	default:
+		return errors.New("invalid exif byte order")
+	}
+	var ifd0 []exifEntry
+	if ifd0, err = r.ifd(r.order.Uint32(data[4:])); err != nil {
+		return
+	}
+	var dateTime string
+	for _, entry := range ifd0 {
+		switch entry.tag {
+		case tagMake:
+			info.Make = r.ascii(entry)
+		case tagModel:
+			info.Model = r.ascii(entry)
+		case tagOrientation:
+			info.ExifOrientation = int(r.uint(entry))
+		case tagDateTime:
+			dateTime = r.ascii(entry)
+		case tagExifIFD:
+			if sub, subErr := r.ifd(r.uint(entry)); subErr == nil {
+				for _, subEntry := range sub {
+					if subEntry.tag == tagDateTimeOriginal {
+						dateTime = r.ascii(subEntry)
+					}
+				}
+			}
+		case tagGPSIFD:
+			if sub, subErr := r.ifd(r.uint(entry)); subErr == nil {
+				for _, subEntry := range sub {
+					if subEntry.tag == tagGPSLatitude {
+						info.HasGPS = true
+					}
+				}
+			}
+		}
+	}
+	if dateTime != "" {
+		if taken, parseErr := time.Parse(exifDateLayout, dateTime); parseErr == nil {
+			info.DateTaken = taken
+		}
+	}
+	return
+}
+
+func exifTypeSize(kind uint16) uint32 {
+	switch kind {
+	case 1, 2, 6, 7:
+		return 1
+	case 3, 8:
+		return 2
+	case 4, 9, 11:
+		return 4
+	case 5, 10, 12:
+		return 8
+	}
+	return 0
+}
+
+func (r *exifReader) ifd(offset uint32) (entries []exifEntry, err error) {
+	if int(offset)+2 > len(r.data) {
+		return nil, errors.New("ifd offset out of range")
+	}
+	count := int(r.order.Uint16(r.data[offset:]))
+	pos := int(offset) + 2
+	for i := 0; i < count; i++ {
+		if pos+12 > len(r.data) {
+			return nil, errors.New("ifd entry out of range")
+		}
+		raw := r.data[pos : pos+12]
+		entry := exifEntry{
+			tag:   r.order.Uint16(raw),
+			kind:  r.order.Uint16(raw[2:]),
+			count: r.order.Uint32(raw[4:]),
+		}
+		size := exifTypeSize(entry.kind) * entry.count
+		if size <= 4 {
+			entry.value = raw[8 : 8+size]
+		} else if valueOffset := r.order.Uint32(raw[8:]); int(valueOffset)+int(size) <= len(r.data) {
+			entry.value = r.data[valueOffset : valueOffset+size]
+		}
+		entries = append(entries, entry)
+		pos += 12
+	}
+	return
+}
+
+func (r *exifReader) ascii(entry exifEntry) string {
+	if entry.kind != exifTypeASCII {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(entry.value), "\x00"))
+}
+
+func (r *exifReader) uint(entry exifEntry) uint32 {
+	switch {
+	case entry.kind == exifTypeShort && len(entry.value) >= 2:
+		return uint32(r.order.Uint16(entry.value))
+	case entry.kind == exifTypeLong && len(entry.value) >= 4:
+		return r.order.Uint32(entry.value)
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+package imagemeta
+
+import (
+	"bufio"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Orientation is string const enum describing shape of displayed image
+type Orientation string
+
+const (
+	Landscape Orientation = "landscape"
+	Portrait  Orientation = "portrait"
+	Square    Orientation = "square"
+)
+
+// Info holds image dimensions decoded from image header and subset of EXIF data. EXIF fields are zero values if image
+// doesn't contain EXIF or given tag.
+type Info struct {
+	Format string
+	Width  int
+	Height int
+
+	// ExifOrientation is raw value of EXIF orientation tag (1-8), 0 if missing
+	ExifOrientation int
+	Make            string
+	Model           string
+	DateTaken       time.Time
+	HasGPS          bool
+}
+
+// DisplaySize returns dimensions of image after applying EXIF orientation. Orientations 5-8 rotate image by 90 degrees.
+func (i *Info) DisplaySize() (width, height int) {
+	if i.ExifOrientation >= 5 && i.ExifOrientation <= 8 {
+		return i.Height, i.Width
+	}
+	return i.Width, i.Height
+}
+
+// Orientation returns shape of displayed image
+func (i *Info) Orientation() Orientation {
+	width, height := i.DisplaySize()
+	switch {
+	case width > height:
+		return Landscape
+	case width < height:
+		return Portrait
+	}
+	return Square
+}
+
+// ByPath reads image info from file. Only image header and metadata segments are read, pixel data isn't decoded.
+func ByPath(path string) (result *Info, err error) {
+	var handle *os.File
+	if handle, err = os.Open(path); err != nil {
+		err = errors.Wrap(err, "os.Open")
+		return
+	}
+	defer handle.Close()
+	return Read(handle)
+}
+
+// Read reads image info from r
+func Read(r io.ReadSeeker) (result *Info, err error) {
+	var config image.Config
+	result = &Info{}
+	if config, result.Format, err = image.DecodeConfig(bufio.NewReader(r)); err != nil {
+		err = errors.Wrap(err, "image.DecodeConfig")
+		return
+	}
+	result.Width, result.Height = config.Width, config.Height
+	var size int64
+	if size, err = r.Seek(0, io.SeekEnd); err != nil {
+		err = errors.Wrap(err, "seek")
+		return
+	}
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		err = errors.Wrap(err, "seek")
+		return
+	}
+	var exif []byte
+	switch result.Format {
+	case "jpeg":
+		exif, err = findJpegExif(bufio.NewReader(r))
+	case "png":
+		exif, err = findPngExif(bufio.NewReader(r), size)
+	}
+	if err != nil {
+		err = errors.Wrap(err, "exif")
+		return
+	}
+	if exif != nil {
+		// broken EXIF shouldn't make dimensions unavailable
+		_ = parseExif(exif, result)
+	}
+	return
+}
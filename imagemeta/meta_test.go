@@ -0,0 +1,156 @@
+package imagemeta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testExifEntry struct {
+	tag, kind uint16
+	count     uint32
+	value     uint32
+}
+
+// testExif builds little endian TIFF structure: IFD0 (make, model, orientation, exif and gps pointers), exif IFD
+// with DateTimeOriginal and GPS IFD with latitude tag
+func testExif() []byte {
+	le := binary.LittleEndian
+	const (
+		ifd0Offset = 8
+		ifd0Size   = 2 + 5*12 + 4
+		makeOffset = ifd0Offset + ifd0Size
+		modelOff   = makeOffset + 8
+		exifOffset = modelOff + 8
+		exifSize   = 2 + 12 + 4
+		dateOffset = exifOffset + exifSize
+		gpsOffset  = dateOffset + 20
+	)
+	buf := &bytes.Buffer{}
+	buf.WriteString("II")
+	binary.Write(buf, le, uint16(42))
+	binary.Write(buf, le, uint32(ifd0Offset))
+	writeIFD := func(entries []testExifEntry) {
+		binary.Write(buf, le, uint16(len(entries)))
+		for _, entry := range entries {
+			binary.Write(buf, le, entry)
+		}
+		binary.Write(buf, le, uint32(0))
+	}
+	writeIFD([]testExifEntry{
+		{tagMake, exifTypeASCII, 8, makeOffset},
+		{tagModel, exifTypeASCII, 8, modelOff},
+		{tagOrientation, exifTypeShort, 1, 6},
+		{tagExifIFD, exifTypeLong, 1, exifOffset},
+		{tagGPSIFD, exifTypeLong, 1, gpsOffset},
+	})
+	buf.WriteString("TestCam\x00")
+	buf.WriteString("X100\x00\x00\x00\x00")
+	writeIFD([]testExifEntry{{tagDateTimeOriginal, exifTypeASCII, 20, dateOffset}})
+	buf.WriteString("2019:05:04 10:11:12\x00")
+	writeIFD([]testExifEntry{{tagGPSLatitude, 5, 3, 0}})
+	return buf.Bytes()
+}
+
+func testJpeg(t *testing.T, exif []byte) []byte {
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, image.NewGray(image.Rect(0, 0, 40, 30)), nil); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+	if exif == nil {
+		return encoded
+	}
+	segment := append(append([]byte{}, exifHeader...), exif...)
+	result := []byte{0xff, 0xd8, 0xff, 0xe1, 0, 0}
+	binary.BigEndian.PutUint16(result[4:], uint16(len(segment)+2))
+	result = append(result, segment...)
+	return append(result, encoded[2:]...)
+}
+
+// testPng returns PNG with chunk of given type and claimed length (followed by data) inserted after IHDR
+func testPng(t *testing.T, chunkType string, length uint32, data []byte) []byte {
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, image.NewGray(image.Rect(0, 0, 40, 30))); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+	const ihdrEnd = 8 + 8 + 13 + 4
+	chunk := make([]byte, 8)
+	binary.BigEndian.PutUint32(chunk, length)
+	copy(chunk[4:], chunkType)
+	chunk = append(append(chunk, data...), 0, 0, 0, 0)
+	return append(append(append([]byte{}, encoded[:ihdrEnd]...), chunk...), encoded[ihdrEnd:]...)
+}
+
+func TestRead(t *testing.T) {
+	t.Run("JpegWithExif", func(t *testing.T) {
+		info, err := Read(bytes.NewReader(testJpeg(t, testExif())))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, &Info{
+			Format:          "jpeg",
+			Width:           40,
+			Height:          30,
+			ExifOrientation: 6,
+			Make:            "TestCam",
+			Model:           "X100",
+			DateTaken:       time.Date(2019, 5, 4, 10, 11, 12, 0, time.UTC),
+			HasGPS:          true,
+		}, info)
+		assert.Equal(t, Portrait, info.Orientation())
+	})
+	t.Run("JpegWithoutExif", func(t *testing.T) {
+		info, err := Read(bytes.NewReader(testJpeg(t, nil)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, &Info{Format: "jpeg", Width: 40, Height: 30}, info)
+		assert.Equal(t, Landscape, info.Orientation())
+	})
+	t.Run("PngWithExif", func(t *testing.T) {
+		exif := testExif()
+		info, err := Read(bytes.NewReader(testPng(t, "eXIf", uint32(len(exif)), exif)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "TestCam", info.Make)
+	})
+	t.Run("PngOversizedChunks", func(t *testing.T) {
+		for _, chunkType := range []string{"eXIf", "tEXt"} {
+			info, err := Read(bytes.NewReader(testPng(t, chunkType, 0xffffffff, nil)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, &Info{Format: "png", Width: 40, Height: 30}, info, chunkType)
+		}
+	})
+}
+
+func TestByPath(t *testing.T) {
+	testExpectations := []struct {
+		path   string
+		format string
+	}{
+		{"../test_files/mime/image.png", "png"},
+		{"../test_files/mime/image.jpg", "jpeg"},
+		{"../test_files/mime/image-gif-fake-audio.mp3", "gif"},
+	}
+	for _, expectation := range testExpectations {
+		info, err := ByPath(expectation.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, expectation.format, info.Format)
+		assert.Equal(t, Square, info.Orientation())
+	}
+	_, err := ByPath("../test_files/mime/text.txt")
+	assert.Error(t, err)
+}
@@ -0,0 +1,227 @@
+package finder
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/duffpl/go-finder/imagehash"
+	"github.com/duffpl/go-finder/imagemeta"
+	"github.com/pkg/errors"
+)
+
+const (
+	perceptualHashAttr = "image-hash:perceptual"
+	imageInfoAttr      = "image-info"
+)
+
+// ImageInfo returns dimensions and EXIF data of image file. Only image header is read, pixels aren't decoded.
+func ImageInfo(fiex file.FileInfoEx) (info *imagemeta.Info, err error) {
+	var v interface{}
+	if v, err = file.Attr(fiex, imageInfoAttr, func(path string) (interface{}, error) {
+		return imagemeta.ByPath(path)
+	}); err != nil {
+		return
+	}
+	info = v.(*imagemeta.Info)
+	return
+}
+
+func (f *Finder) addImageInfoFilter(name string, cb func(info *imagemeta.Info) bool) {
+	f.addInfoFilter(name, CostHead, func(fiex file.FileInfoEx) (interface{}, error) {
+		return ImageInfo(fiex)
+	}, func(info interface{}) bool {
+		return cb(info.(*imagemeta.Info))
+	})
+}
+
+// ImageWidth adds matching against width of displayed image (EXIF orientation is taken into account). Valid operators
+// are available in CmpOperator const.
+func (f *Finder) ImageWidth(cmpOp CmpOperator, width int) *Finder {
+	if f.lastErr != nil { return f }
+	if !isCmpOperatorValid(cmpOp) {
+		f.lastErr = Errors.InvalidCmpOperator
+		return f
+	}
+	f.addImageInfoFilter("ImageWidth", func(info *imagemeta.Info) bool {
+		displayWidth, _ := info.DisplaySize()
+		return compareInt64(cmpOp, int64(displayWidth), int64(width))
+	})
+	return f
+}
+
+// ImageHeight adds matching against height of displayed image (EXIF orientation is taken into account). Valid
+// operators are available in CmpOperator const.
+func (f *Finder) ImageHeight(cmpOp CmpOperator, height int) *Finder {
+	if f.lastErr != nil { return f }
+	if !isCmpOperatorValid(cmpOp) {
+		f.lastErr = Errors.InvalidCmpOperator
+		return f
+	}
+	f.addImageInfoFilter("ImageHeight", func(info *imagemeta.Info) bool {
+		_, displayHeight := info.DisplaySize()
+		return compareInt64(cmpOp, int64(displayHeight), int64(height))
+	})
+	return f
+}
+
+// ImageOrientation adds matching against shape of displayed image
+func (f *Finder) ImageOrientation(orientation imagemeta.Orientation) *Finder {
+	if f.lastErr != nil { return f }
+	f.addImageInfoFilter("ImageOrientation", func(info *imagemeta.Info) bool {
+		return info.Orientation() == orientation
+	})
+	return f
+}
+
+// CameraMake adds matching against EXIF camera make using regexp pattern
+func (f *Finder) CameraMake(pattern string) *Finder {
+	if f.lastErr != nil { return f }
+	var compiled *regexp.Regexp
+	if compiled, f.lastErr = regexp.Compile(pattern); f.lastErr != nil {
+		return f
+	}
+	f.addImageInfoFilter("CameraMake", func(info *imagemeta.Info) bool {
+		return compiled.MatchString(info.Make)
+	})
+	return f
+}
+
+// CameraModel adds matching against EXIF camera model using regexp pattern
+func (f *Finder) CameraModel(pattern string) *Finder {
+	if f.lastErr != nil { return f }
+	var compiled *regexp.Regexp
+	if compiled, f.lastErr = regexp.Compile(pattern); f.lastErr != nil {
+		return f
+	}
+	f.addImageInfoFilter("CameraModel", func(info *imagemeta.Info) bool {
+		return compiled.MatchString(info.Model)
+	})
+	return f
+}
+
+// TakenBetween adds matching against EXIF date taken. Range includes from and excludes to. Zero value of either bound
+// leaves range open on that side. Images without date aren't matched.
+func (f *Finder) TakenBetween(from, to time.Time) *Finder {
+	if f.lastErr != nil { return f }
+	f.addImageInfoFilter("TakenBetween", func(info *imagemeta.Info) bool {
+		return inTimeRange(info.DateTaken, from, to)
+	})
+	return f
+}
+
+// HasGPS adds matching of images with GPS position in EXIF data
+func (f *Finder) HasGPS() *Finder {
+	if f.lastErr != nil { return f }
+	f.addImageInfoFilter("HasGPS", func(info *imagemeta.Info) bool {
+		return info.HasGPS
+	})
+	return f
+}
+
+func inTimeRange(t, from, to time.Time) bool {
+	if t.IsZero() {
+		return false
+	}
+	return (from.IsZero() || !t.Before(from)) && (to.IsZero() || t.Before(to))
+}
+
+// ImagePerceptualHash returns perceptual hash of image file. Hash is cached on FileInfoEx so it's computed only once
+// for SimilarImage and GroupSimilarImages.
+func ImagePerceptualHash(fiex file.FileInfoEx) (hash imagehash.Hash, err error) {
+	var v interface{}
+	if v, err = file.Attr(fiex, perceptualHashAttr, func(path string) (interface{}, error) {
+		return imagehash.ByPath(imagehash.Perceptual, path)
+	}); err != nil {
+		return
+	}
+	hash = v.(imagehash.Hash)
+	return
+}
+
+// SimilarImage adds matching against perceptual hash of reference image. Matches images which hash differs from
+// reference one by at most maxHammingDistance bits (0-64). Files that can't be decoded as PNG, JPEG or GIF aren't
+// matched so it's best to narrow results with MimeRegexp("^image") first.
+func (f *Finder) SimilarImage(path string, maxHammingDistance int) *Finder {
+	if f.lastErr != nil { return f }
+	var refHash imagehash.Hash
+	if refHash, f.lastErr = imagehash.ByPath(imagehash.Perceptual, path); f.lastErr != nil {
+		f.lastErr = errors.Wrap(f.lastErr, "SimilarImage")
+		return f
+	}
+	f.addFilter("SimilarImage", CostContent, func(fiex file.FileInfoEx) (result bool, err error) {
+		var hash imagehash.Hash
+		if hash, err = ImagePerceptualHash(fiex); err != nil {
+			err = errors.Wrap(err, "SimilarImage")
+			return
+		}
+		result = imagehash.Distance(refHash, hash) <= maxHammingDistance
+		return
+	})
+	return f
+}
+
+// GroupSimilarImages globs files and clusters images which perceptual hashes differ by at most maxHammingDistance
+// bits. Clustering is transitive: if A is similar to B and B to C all three end up in same group. Only groups with at
+// least two images are returned. Files that can't be decoded are skipped.
+func (f *Finder) GroupSimilarImages(pattern string, maxHammingDistance int) (groups [][]file.FileInfoEx, err error) {
+	var globResult []file.FileInfoEx
+	if globResult, err = f.Glob(pattern); err != nil {
+		return
+	}
+	hashes := make([]imagehash.Hash, len(globResult))
+	decoded := make([]bool, len(globResult))
+	runParallel(f.numCheckers, len(globResult), func(idx int) {
+		var hashErr error
+		hashes[idx], hashErr = ImagePerceptualHash(globResult[idx])
+		decoded[idx] = hashErr == nil
+	})
+	parents := make([]int, len(globResult))
+	for i := range parents {
+		parents[i] = i
+	}
+	root := func(i int) int {
+		for parents[i] != i {
+			parents[i] = parents[parents[i]]
+			i = parents[i]
+		}
+		return i
+	}
+	for i := range globResult {
+		if !decoded[i] {
+			continue
+		}
+		for j := i + 1; j < len(globResult); j++ {
+			if decoded[j] && imagehash.Distance(hashes[i], hashes[j]) <= maxHammingDistance {
+				parents[root(j)] = root(i)
+			}
+		}
+	}
+	byRoot := map[int][]file.FileInfoEx{}
+	for i, info := range globResult {
+		if decoded[i] {
+			byRoot[root(i)] = append(byRoot[root(i)], info)
+		}
+	}
+	for _, group := range byRoot {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			return fileInfoExPath(group[i]) < fileInfoExPath(group[j])
+		})
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return fileInfoExPath(groups[i][0]) < fileInfoExPath(groups[j][0])
+	})
+	return
+}
+
+func fileInfoExPath(fiex file.FileInfoEx) string {
+	if abs, err := fiex.Abs(); err == nil {
+		return abs
+	}
+	return fiex.Name()
+}
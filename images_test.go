@@ -0,0 +1,79 @@
+package finder
+
+import (
+	"image"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/duffpl/go-finder/imagemeta"
+	"github.com/duffpl/go-finder/internal/testimage"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestImages(t *testing.T) string {
+	dir := createTestTree(t, map[string]string{"text.txt": "not an image"})
+	for name, img := range map[string]image.Image{
+		"original.png":      testimage.Pattern(128, false),
+		"small-copy.jpg":    testimage.Pattern(40, false),
+		"inverted.png":      testimage.Pattern(128, true),
+		"inverted-copy.jpg": testimage.Pattern(64, true),
+	} {
+		if err := testimage.Write(filepath.Join(dir, name), img); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestFinder_SimilarImage(t *testing.T) {
+	dir := createTestImages(t)
+	result, err := New().
+		SimilarImage(filepath.Join(dir, "original.png"), 10).
+		Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"original.png", "small-copy.jpg"}, getFileNamesFromResult(result))
+	t.Run("InvalidReference", func(t *testing.T) {
+		_, err := New().SimilarImage(filepath.Join(dir, "text.txt"), 10).Glob(filepath.Join(dir, "*"))
+		assert.Error(t, err)
+	})
+}
+
+func TestFinder_GroupSimilarImages(t *testing.T) {
+	dir := createTestImages(t)
+	groups, err := New().GroupSimilarImages(filepath.Join(dir, "*"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names [][]string
+	for _, group := range groups {
+		names = append(names, getFileNamesFromResult(group))
+	}
+	assert.Equal(t, [][]string{{"inverted-copy.jpg", "inverted.png"}, {"original.png", "small-copy.jpg"}}, names)
+}
+
+func TestFinder_ImageInfoFilters(t *testing.T) {
+	mockGlob := newMockGlobFunc([]file.FileInfoEx{
+		withAttr("hd", imageInfoAttr, &imagemeta.Info{Width: 1920, Height: 1080, Make: "Canon", Model: "EOS 5D",
+			DateTaken: time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC), HasGPS: true}),
+		withAttr("hd-rotated", imageInfoAttr, &imagemeta.Info{Width: 1920, Height: 1080, ExifOrientation: 6, Make: "NIKON",
+			DateTaken: time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)}),
+		withAttr("thumb", imageInfoAttr, &imagemeta.Info{Width: 100, Height: 100}),
+	})
+	runFilterExpectations(t, mockGlob, "test-glob", []filterExpectation{
+		{"ImageWidth", New().ImageWidth(MoreOrEqual, 1920), []string{"hd"}},
+		{"ImageHeight", New().ImageHeight(MoreThan, 1000), []string{"hd", "hd-rotated"}},
+		{"ImageOrientation", New().ImageOrientation(imagemeta.Portrait), []string{"hd-rotated"}},
+		{"CameraMake", New().CameraMake("(?i)^canon"), []string{"hd"}},
+		{"CameraModel", New().CameraModel("5D"), []string{"hd"}},
+		{"TakenBetween", New().TakenBetween(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}), []string{"hd"}},
+		{"HasGPS", New().HasGPS(), []string{"hd"}},
+	})
+	t.Run("InvalidOperator", func(t *testing.T) {
+		_, err := New().ImageWidth("~", 1).Glob("*")
+		assert.Equal(t, Errors.InvalidCmpOperator, err)
+	})
+}
@@ -0,0 +1,43 @@
+// Package testimage generates deterministic images for tests of image related packages
+package testimage
+
+import (
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+)
+
+// Pattern returns size x size grayscale gradient overlaid with checkerboard. Inverted pattern is perceptually
+// different from original one, while resized or recompressed copies stay similar.
+func Pattern(size int, inverted bool) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			v := uint8((x*x + 3*y) * 255 / (size*size + 3*size))
+			if (x*4/size+y*4/size)%2 == 0 {
+				v /= 2
+			}
+			if inverted {
+				v = 255 - v
+			}
+			img.Set(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	return img
+}
+
+// Write encodes img to path as JPEG (quality 50) if path has .jpg extension, PNG otherwise
+func Write(path string, img image.Image) (err error) {
+	var handle *os.File
+	if handle, err = os.Create(path); err != nil {
+		return
+	}
+	defer handle.Close()
+	if filepath.Ext(path) == ".jpg" {
+		return jpeg.Encode(handle, img, &jpeg.Options{Quality: 50})
+	}
+	return png.Encode(handle, img)
+}
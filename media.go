@@ -0,0 +1,87 @@
+package finder
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/duffpl/go-finder/mediameta"
+)
+
+const mediaInfoAttr = "media-info"
+
+// MediaInfo returns duration, bitrate, sample rate, channels and tags of audio/video file (MP3, MP4/M4A, WAV, FLAC).
+// MP3 files without Xing/VBRI header are treated as constant bitrate.
+func MediaInfo(fiex file.FileInfoEx) (info *mediameta.Info, err error) {
+	var v interface{}
+	if v, err = file.Attr(fiex, mediaInfoAttr, func(path string) (interface{}, error) {
+		return mediameta.ByPath(path)
+	}); err != nil {
+		return
+	}
+	info = v.(*mediameta.Info)
+	return
+}
+
+func (f *Finder) addMediaInfoFilter(name string, cb func(info *mediameta.Info) bool) {
+	f.addInfoFilter(name, CostHead, func(fiex file.FileInfoEx) (interface{}, error) {
+		return MediaInfo(fiex)
+	}, func(info interface{}) bool {
+		return cb(info.(*mediameta.Info))
+	})
+}
+
+// MediaDuration adds matching against duration of audio/video file. Valid operators are available in CmpOperator
+// const.
+func (f *Finder) MediaDuration(cmpOp CmpOperator, duration time.Duration) *Finder {
+	if f.lastErr != nil { return f }
+	if !isCmpOperatorValid(cmpOp) {
+		f.lastErr = Errors.InvalidCmpOperator
+		return f
+	}
+	f.addMediaInfoFilter("MediaDuration", func(info *mediameta.Info) bool {
+		return compareInt64(cmpOp, int64(info.Duration), int64(duration))
+	})
+	return f
+}
+
+// MediaBitrate adds matching against average bitrate (bits per second) of audio/video file
+func (f *Finder) MediaBitrate(cmpOp CmpOperator, bitrate int) *Finder {
+	if f.lastErr != nil { return f }
+	if !isCmpOperatorValid(cmpOp) {
+		f.lastErr = Errors.InvalidCmpOperator
+		return f
+	}
+	f.addMediaInfoFilter("MediaBitrate", func(info *mediameta.Info) bool {
+		return compareInt64(cmpOp, int64(info.Bitrate), int64(bitrate))
+	})
+	return f
+}
+
+// MediaSampleRate adds matching against audio sample rate in Hz
+func (f *Finder) MediaSampleRate(cmpOp CmpOperator, sampleRate int) *Finder {
+	if f.lastErr != nil { return f }
+	if !isCmpOperatorValid(cmpOp) {
+		f.lastErr = Errors.InvalidCmpOperator
+		return f
+	}
+	f.addMediaInfoFilter("MediaSampleRate", func(info *mediameta.Info) bool {
+		return compareInt64(cmpOp, int64(info.SampleRate), int64(sampleRate))
+	})
+	return f
+}
+
+// MediaTag adds matching against tag value using regexp pattern. Tag names are available as mediameta.Tag* consts,
+// e.g. MediaTag(mediameta.TagArtist, "^Queen$"). Files without given tag aren't matched.
+func (f *Finder) MediaTag(tag string, pattern string) *Finder {
+	if f.lastErr != nil { return f }
+	var compiled *regexp.Regexp
+	if compiled, f.lastErr = regexp.Compile(pattern); f.lastErr != nil {
+		return f
+	}
+	f.addMediaInfoFilter("MediaTag", func(info *mediameta.Info) bool {
+		value, found := info.Tags[tag]
+		return found && compiled.MatchString(value)
+	})
+	return f
+}
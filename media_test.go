@@ -0,0 +1,35 @@
+package finder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/duffpl/go-finder/mediameta"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinder_MediaInfoFilters(t *testing.T) {
+	mockGlob := newMockGlobFunc([]file.FileInfoEx{
+		withAttr("long", mediaInfoAttr, &mediameta.Info{Duration: 12 * time.Minute, Bitrate: 320000, SampleRate: 44100,
+			Tags: map[string]string{mediameta.TagArtist: "Artist X"}}),
+		withAttr("short", mediaInfoAttr, &mediameta.Info{Duration: 3 * time.Minute, Bitrate: 128000, SampleRate: 48000,
+			Tags: map[string]string{}}),
+	})
+	runFilterExpectations(t, mockGlob, "test-glob", []filterExpectation{
+		{"MediaDuration", New().MediaDuration(MoreThan, 10*time.Minute), []string{"long"}},
+		{"MediaBitrate", New().MediaBitrate(LessThan, 192000), []string{"short"}},
+		{"MediaSampleRate", New().MediaSampleRate(Equal, 48000), []string{"short"}},
+		{"MediaTag", New().MediaTag(mediameta.TagArtist, "X$"), []string{"long"}},
+	})
+}
+
+func TestFinder_MediaDuration_integration(t *testing.T) {
+	result, err := New().
+		MediaDuration(LessThan, time.Second).
+		Glob("./test_files/mime/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"audio-no-extension", "audio.mp3"}, getFileNamesFromResult(result))
+}
@@ -0,0 +1,91 @@
+package mediameta
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	flacStreamInfo    = 0
+	flacVorbisComment = 4
+)
+
+var vorbisCommentTags = map[string]string{
+	"TITLE":       TagTitle,
+	"ARTIST":      TagArtist,
+	"ALBUM":       TagAlbum,
+	"DATE":        TagYear,
+	"GENRE":       TagGenre,
+	"TRACKNUMBER": TagTrack,
+}
+
+// readFlac reads STREAMINFO and VORBIS_COMMENT metadata blocks
+func readFlac(r io.ReaderAt, size int64, info *Info) (err error) {
+	streamInfoFound := false
+	offset := int64(4)
+	for last := false; !last; {
+		var header []byte
+		if header, err = readAt(r, offset, 4); err != nil {
+			return errors.Wrap(err, "metadata block header")
+		}
+		last = header[0]&0x80 != 0
+		blockType := header[0] & 0x7f
+		blockSize := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+		offset += 4
+		switch blockType {
+		case flacStreamInfo:
+			var block []byte
+			if block, err = readAt(r, offset, 18); err != nil {
+				return errors.Wrap(err, "STREAMINFO")
+			}
+			packed := binary.BigEndian.Uint64(block[10:])
+			info.SampleRate = int(packed >> 44)
+			info.Channels = int(packed>>41&7) + 1
+			totalSamples := int64(packed & (1<<36 - 1))
+			info.Duration = durationFromRate(totalSamples, info.SampleRate)
+			streamInfoFound = true
+		case flacVorbisComment:
+			var block []byte
+			if block, err = readAt(r, offset, blockSize); err != nil {
+				return errors.Wrap(err, "VORBIS_COMMENT")
+			}
+			readVorbisComment(block, info)
+		}
+		offset += int64(blockSize)
+	}
+	if !streamInfoFound {
+		return errors.New("STREAMINFO not found")
+	}
+	info.Bitrate = bitrateFromSize(size-offset, info.Duration)
+	return
+}
+
+func readVorbisComment(block []byte, info *Info) {
+	le := binary.LittleEndian
+	if len(block) < 4 {
+		return
+	}
+	pos := 4 + int(le.Uint32(block))
+	if pos+4 > len(block) {
+		return
+	}
+	count := int(le.Uint32(block[pos:]))
+	pos += 4
+	for i := 0; i < count && pos+4 <= len(block); i++ {
+		length := int(le.Uint32(block[pos:]))
+		pos += 4
+		if pos+length > len(block) {
+			return
+		}
+		comment := string(block[pos : pos+length])
+		pos += length
+		if idx := strings.IndexByte(comment, '='); idx > 0 {
+			if key, found := vorbisCommentTags[strings.ToUpper(comment[:idx])]; found {
+				info.setTag(key, comment[idx+1:])
+			}
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package mediameta
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Tag keys used in Info.Tags. Format specific tag names are normalized to these.
+const (
+	TagTitle  = "title"
+	TagArtist = "artist"
+	TagAlbum  = "album"
+	TagYear   = "year"
+	TagGenre  = "genre"
+	TagTrack  = "track"
+)
+
+// Info holds audio/video stream properties and tags. Fields that couldn't be determined are zero values. Bitrate is
+// average bitrate in bits per second.
+type Info struct {
+	Format     string
+	Duration   time.Duration
+	Bitrate    int
+	SampleRate int
+	Channels   int
+	Tags       map[string]string
+}
+
+var ErrUnknownFormat = errors.New("unknown media format")
+
+// ByPath reads media info from file. Supported formats are MP3 (ID3v1/ID3v2 tags and MPEG frame headers), MP4/M4A,
+// WAV and FLAC. Only headers and metadata are read.
+func ByPath(path string) (result *Info, err error) {
+	var handle *os.File
+	if handle, err = os.Open(path); err != nil {
+		err = errors.Wrap(err, "os.Open")
+		return
+	}
+	defer handle.Close()
+	var stat os.FileInfo
+	if stat, err = handle.Stat(); err != nil {
+		err = errors.Wrap(err, "stat")
+		return
+	}
+	return Read(handle, stat.Size())
+}
+
+// Read reads media info from r which holds size bytes
+func Read(r io.ReaderAt, size int64) (result *Info, err error) {
+	head := make([]byte, 12)
+	if _, err = r.ReadAt(head, 0); err != nil && err != io.EOF {
+		err = errors.Wrap(err, "read")
+		return
+	}
+	result = &Info{Tags: map[string]string{}}
+	switch {
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		result.Format = "flac"
+		err = readFlac(r, size, result)
+	case bytes.HasPrefix(head, []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WAVE")):
+		result.Format = "wav"
+		err = readWav(r, size, result)
+	case bytes.Equal(head[4:8], []byte("ftyp")):
+		result.Format = "mp4"
+		err = readMp4(r, size, result)
+	case bytes.HasPrefix(head, []byte("ID3")) || isMpegFrameSync(head):
+		result.Format = "mp3"
+		err = readMp3(r, size, result)
+	default:
+		err = ErrUnknownFormat
+	}
+	if err != nil {
+		result = nil
+		err = errors.Wrap(err, "read")
+	}
+	return
+}
+
+func (i *Info) setTag(key, value string) {
+	if value != "" {
+		i.Tags[key] = value
+	}
+}
+
+func bitrateFromSize(size int64, duration time.Duration) int {
+	if duration <= 0 {
+		return 0
+	}
+	return int(float64(size*8) / duration.Seconds())
+}
+
+// durationFromRate returns time needed to play count units (samples, bytes, bits) at rate units per second
+func durationFromRate(count int64, rate int) time.Duration {
+	if rate <= 0 {
+		return 0
+	}
+	return time.Duration(float64(count) / float64(rate) * float64(time.Second))
+}
+
+// maxReadSize caps single read of metadata structure. Longer structures are truncated.
+const maxReadSize = 16 << 20
+
+// readAt reads length bytes at offset. Lengths come from file headers, so data is read incrementally and buffer never
+// grows past what file actually holds. io.EOF is returned for short read.
+func readAt(r io.ReaderAt, offset int64, length int) (data []byte, err error) {
+	if length < 0 {
+		return nil, errors.New("negative length")
+	}
+	if length > maxReadSize {
+		length = maxReadSize
+	}
+	if data, err = ioutil.ReadAll(io.NewSectionReader(r, offset, int64(length))); err == nil && len(data) < length {
+		err = io.EOF
+	}
+	return
+}
@@ -0,0 +1,169 @@
+package mediameta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByPath_Mp3(t *testing.T) {
+	info, err := ByPath("../test_files/mime/audio.mp3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "mp3", info.Format)
+	assert.Equal(t, 8000, info.SampleRate)
+	assert.Equal(t, 8000, info.Bitrate)
+	assert.Equal(t, 1, info.Channels)
+	assert.Equal(t, 504*time.Millisecond, info.Duration)
+	assert.Equal(t, map[string]string{
+		TagTitle:  "250 Milliseconds of Silence",
+		TagArtist: "Anar Software LLC",
+		TagAlbum:  "Blank Audio",
+	}, info.Tags)
+}
+
+func TestRead(t *testing.T) {
+	le, be := binary.LittleEndian, binary.BigEndian
+	chunk := func(id string, body []byte, order binary.ByteOrder) []byte {
+		buf := &bytes.Buffer{}
+		if order == be {
+			binary.Write(buf, be, uint32(len(body)+8))
+			buf.WriteString(id)
+		} else {
+			buf.WriteString(id)
+			binary.Write(buf, le, uint32(len(body)))
+		}
+		buf.Write(body)
+		return buf.Bytes()
+	}
+	concat := func(parts ...[]byte) []byte {
+		return bytes.Join(parts, nil)
+	}
+	packed := func(order binary.ByteOrder, values ...interface{}) []byte {
+		buf := &bytes.Buffer{}
+		for _, v := range values {
+			binary.Write(buf, order, v)
+		}
+		return buf.Bytes()
+	}
+
+	wavFormat := packed(le, uint16(1), uint16(2), uint32(44100), uint32(44100*4), uint16(4), uint16(16))
+	wavInfo := concat([]byte("INFO"), chunk("INAM", []byte("Wave title\x00\x00"), le))
+	wavBody := concat([]byte("WAVE"), chunk("fmt ", wavFormat, le), chunk("LIST", wavInfo, le),
+		chunk("data", make([]byte, 44100*4/2), le))
+	wav := chunk("RIFF", wavBody, le)
+
+	streamInfo := make([]byte, 34)
+	be.PutUint64(streamInfo[10:], uint64(48000)<<44|uint64(1)<<41|uint64(15)<<36|96000)
+	comment := concat(packed(le, uint32(4)), []byte("test"), packed(le, uint32(1)),
+		packed(le, uint32(len("ARTIST=Someone"))), []byte("ARTIST=Someone"))
+	flac := concat([]byte("fLaC"), []byte{0, 0, 0, 34}, streamInfo,
+		[]byte{0x80 | flacVorbisComment, 0, 0, byte(len(comment))}, comment, make([]byte, 1000))
+
+	sampleEntry := concat(make([]byte, 16), packed(be, uint16(2), uint16(16), uint32(0), uint32(22050<<16)))
+	mvhd := concat(make([]byte, 12), packed(be, uint32(1000), uint32(5000)), make([]byte, 80))
+	ilst := chunk("ilst", chunk("\xa9nam", chunk("data", concat(make([]byte, 8), []byte("Movie title")), be), be), be)
+	moov := chunk("moov", concat(
+		chunk("mvhd", mvhd, be),
+		chunk("trak", chunk("mdia", concat(
+			chunk("hdlr", concat(make([]byte, 8), []byte("soun"), make([]byte, 12)), be),
+			chunk("minf", chunk("stbl", chunk("stsd", concat(make([]byte, 8), chunk("mp4a", sampleEntry, be)), be), be), be),
+		), be), be),
+		chunk("udta", chunk("meta", concat(make([]byte, 4), ilst), be), be),
+	), be)
+	mp4 := concat(chunk("ftyp", []byte("M4A \x00\x00\x00\x00"), be), moov, chunk("mdat", make([]byte, 5000), be))
+
+	testExpectations := []struct {
+		name     string
+		data     []byte
+		expected *Info
+	}{
+		{"Wav", wav, &Info{Format: "wav", Duration: 500 * time.Millisecond, Bitrate: 44100 * 32, SampleRate: 44100,
+			Channels: 2, Tags: map[string]string{TagTitle: "Wave title"}}},
+		{"Flac", flac, &Info{Format: "flac", Duration: 2 * time.Second, Bitrate: 4000, SampleRate: 48000,
+			Channels: 2, Tags: map[string]string{TagArtist: "Someone"}}},
+		{"Mp4", mp4, &Info{Format: "mp4", Duration: 5 * time.Second, Bitrate: 8000, SampleRate: 22050,
+			Channels: 2, Tags: map[string]string{TagTitle: "Movie title"}}},
+	}
+	for _, expectation := range testExpectations {
+		t.Run(expectation.name, func(t *testing.T) {
+			info, err := Read(bytes.NewReader(expectation.data), int64(len(expectation.data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expectation.expected, info)
+		})
+	}
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := Read(bytes.NewReader([]byte("plain text file")), 15)
+		assert.Error(t, err)
+	})
+}
+
+// sizeTrackingReader records largest buffer passed to ReadAt
+type sizeTrackingReader struct {
+	*bytes.Reader
+	maxRead int
+}
+
+func (r *sizeTrackingReader) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) > r.maxRead {
+		r.maxRead = len(p)
+	}
+	return r.Reader.ReadAt(p, off)
+}
+
+func TestRead_oversizedLengths(t *testing.T) {
+	le := binary.LittleEndian
+	wav := []byte("RIFF\x00\x00\x00\x00WAVEfmt \x10\x00\x00\x00")
+	format := make([]byte, 16)
+	le.PutUint16(format[2:], 2)
+	le.PutUint32(format[4:], 44100)
+	le.PutUint32(format[8:], 44100*4)
+	wav = append(append(wav, format...), []byte("LIST\xff\xff\xff\xffINFOINAM\x05\x00\x00\x00title")...)
+	id3 := append([]byte("ID3\x03\x00\x00\x7f\x7f\x7f\x7f"), make([]byte, 100)...)
+	testExpectations := []struct {
+		name  string
+		data  []byte
+		valid bool
+	}{
+		{"WavList", wav, true},
+		{"Id3v2", id3, false},
+	}
+	for _, expectation := range testExpectations {
+		t.Run(expectation.name, func(t *testing.T) {
+			r := &sizeTrackingReader{Reader: bytes.NewReader(expectation.data)}
+			info, err := Read(r, int64(len(expectation.data)))
+			if expectation.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, "title", info.Tags[TagTitle])
+			} else {
+				assert.Error(t, err)
+			}
+			assert.True(t, r.maxRead <= 1024, "largest read: %d", r.maxRead)
+		})
+	}
+	t.Run("Mp4Nesting", func(t *testing.T) {
+		data := []byte{}
+		for i := 0; i < 10000; i++ {
+			header := make([]byte, 8)
+			binary.BigEndian.PutUint32(header, uint32(len(data)+8))
+			copy(header[4:], "moov")
+			data = append(header, data...)
+		}
+		err := readMp4(bytes.NewReader(data), int64(len(data)), &Info{})
+		assert.Equal(t, errMp4Nesting, err)
+	})
+	t.Run("readAt", func(t *testing.T) {
+		r := &sizeTrackingReader{Reader: bytes.NewReader(make([]byte, 100))}
+		data, err := readAt(r, 90, 1<<30)
+		assert.Equal(t, io.EOF, err)
+		assert.Len(t, data, 10)
+		assert.True(t, r.maxRead <= 1024, "largest read: %d", r.maxRead)
+	})
+}
@@ -0,0 +1,275 @@
+package mediameta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	mpegVersion25 = 0
+	mpegVersion2  = 2
+	mpegVersion1  = 3
+
+	mpegSyncSearchLimit = 64 * 1024
+)
+
+var (
+	mpegBitrates = map[[2]int][16]int{
+		{mpegVersion1, 1}: {0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+		{mpegVersion1, 2}: {0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+		{mpegVersion1, 3}: {0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+		{mpegVersion2, 1}: {0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		{mpegVersion2, 2}: {0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		{mpegVersion2, 3}: {0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	}
+	mpegSampleRates = map[int][3]int{
+		mpegVersion1:  {44100, 48000, 32000},
+		mpegVersion2:  {22050, 24000, 16000},
+		mpegVersion25: {11025, 12000, 8000},
+	}
+	id3v2Frames = map[string]string{
+		"TIT2": TagTitle, "TT2": TagTitle,
+		"TPE1": TagArtist, "TP1": TagArtist,
+		"TALB": TagAlbum, "TAL": TagAlbum,
+		"TYER": TagYear, "TYE": TagYear, "TDRC": TagYear,
+		"TCON": TagGenre, "TCO": TagGenre,
+		"TRCK": TagTrack, "TRK": TagTrack,
+	}
+)
+
+type mpegFrameHeader struct {
+	version, layer int
+	bitrate        int
+	sampleRate     int
+	channels       int
+	padding        int
+}
+
+func isMpegFrameSync(b []byte) bool {
+	return len(b) >= 2 && b[0] == 0xff && b[1]&0xe0 == 0xe0
+}
+
+func parseMpegFrameHeader(b []byte) (h mpegFrameHeader, ok bool) {
+	if len(b) < 4 || !isMpegFrameSync(b) {
+		return
+	}
+	h.version = int(b[1]>>3) & 3
+	h.layer = 4 - int(b[1]>>1)&3
+	bitrateIdx := int(b[2] >> 4)
+	sampleRateIdx := int(b[2]>>2) & 3
+	if h.version == 1 || h.layer == 4 || bitrateIdx == 0 || bitrateIdx == 15 || sampleRateIdx == 3 {
+		return
+	}
+	tableVersion := h.version
+	if tableVersion == mpegVersion25 {
+		tableVersion = mpegVersion2
+	}
+	h.bitrate = mpegBitrates[[2]int{tableVersion, h.layer}][bitrateIdx] * 1000
+	h.sampleRate = mpegSampleRates[h.version][sampleRateIdx]
+	h.padding = int(b[2]>>1) & 1
+	h.channels = 2
+	if b[3]>>6 == 3 {
+		h.channels = 1
+	}
+	return h, true
+}
+
+func (h mpegFrameHeader) samplesPerFrame() int {
+	switch {
+	case h.layer == 1:
+		return 384
+	case h.layer == 3 && h.version != mpegVersion1:
+		return 576
+	}
+	return 1152
+}
+
+// xingOffset returns offset of Xing/Info header from frame start. It's placed right after side information.
+func (h mpegFrameHeader) xingOffset() int {
+	switch {
+	case h.version == mpegVersion1 && h.channels == 2:
+		return 4 + 32
+	case h.version == mpegVersion1 || h.channels == 2:
+		return 4 + 17
+	}
+	return 4 + 9
+}
+
+func readMp3(r io.ReaderAt, size int64, info *Info) (err error) {
+	audioStart := int64(0)
+	var header []byte
+	if header, err = readAt(r, 0, 10); err != nil {
+		return
+	}
+	if bytes.HasPrefix(header, []byte("ID3")) {
+		tagSize := int64(syncSafe(header[6:10]))
+		if tagSize > size-10 {
+			return errors.New("id3v2: tag larger than file")
+		}
+		if err = readId3v2(r, header, tagSize, info); err != nil {
+			return errors.Wrap(err, "id3v2")
+		}
+		audioStart = 10 + tagSize
+	}
+	audioEnd := size
+	if size >= 128 {
+		if tail, tailErr := readAt(r, size-128, 128); tailErr == nil && bytes.HasPrefix(tail, []byte("TAG")) {
+			readId3v1(tail, info)
+			audioEnd -= 128
+		}
+	}
+	var search []byte
+	if search, err = readAt(r, audioStart, mpegSyncSearchLimit); err != nil && err != io.EOF {
+		return
+	}
+	err = nil
+	for i := 0; i+4 <= len(search); i++ {
+		frame, ok := parseMpegFrameHeader(search[i:])
+		if !ok {
+			continue
+		}
+		info.SampleRate = frame.sampleRate
+		info.Channels = frame.channels
+		audioStart += int64(i)
+		if frames := xingFrames(search[i:], frame); frames > 0 {
+			info.Duration = durationFromRate(int64(frames)*int64(frame.samplesPerFrame()), frame.sampleRate)
+			info.Bitrate = bitrateFromSize(audioEnd-audioStart, info.Duration)
+		} else {
+			info.Bitrate = frame.bitrate
+			info.Duration = durationFromRate((audioEnd-audioStart)*8, frame.bitrate)
+		}
+		return
+	}
+	return errors.New("mpeg frame not found")
+}
+
+// xingFrames returns number of frames from Xing/Info or VBRI header, 0 if there is none
+func xingFrames(frame []byte, h mpegFrameHeader) uint32 {
+	if off := h.xingOffset(); len(frame) >= off+12 {
+		tag := string(frame[off : off+4])
+		if (tag == "Xing" || tag == "Info") && frame[off+7]&1 == 1 {
+			return binary.BigEndian.Uint32(frame[off+8:])
+		}
+	}
+	if off := 4 + 32; len(frame) >= off+18 && string(frame[off:off+4]) == "VBRI" {
+		return binary.BigEndian.Uint32(frame[off+14:])
+	}
+	return 0
+}
+
+func syncSafe(b []byte) uint32 {
+	return uint32(b[0]&0x7f)<<21 | uint32(b[1]&0x7f)<<14 | uint32(b[2]&0x7f)<<7 | uint32(b[3]&0x7f)
+}
+
+func readId3v2(r io.ReaderAt, header []byte, tagSize int64, info *Info) (err error) {
+	majorVersion := header[3]
+	var tag []byte
+	if tag, err = readAt(r, 10, int(tagSize)); err != nil {
+		return
+	}
+	if header[5]&0x40 != 0 && majorVersion >= 3 && len(tag) >= 4 {
+		// extended header, its size field is syncsafe in v2.4 and doesn't include itself in v2.3
+		extSize := int(binary.BigEndian.Uint32(tag))
+		if majorVersion == 4 {
+			extSize = int(syncSafe(tag))
+		} else {
+			extSize += 4
+		}
+		if extSize > len(tag) {
+			return errors.New("invalid extended header")
+		}
+		tag = tag[extSize:]
+	}
+	idLen, headerLen := 4, 10
+	if majorVersion == 2 {
+		idLen, headerLen = 3, 6
+	}
+	for len(tag) >= headerLen && tag[0] != 0 {
+		id := string(tag[:idLen])
+		var frameSize int
+		switch majorVersion {
+		case 2:
+			frameSize = int(tag[3])<<16 | int(tag[4])<<8 | int(tag[5])
+		case 3:
+			frameSize = int(binary.BigEndian.Uint32(tag[4:]))
+		default:
+			frameSize = int(syncSafe(tag[4:]))
+		}
+		if frameSize < 0 || headerLen+frameSize > len(tag) {
+			break
+		}
+		if key, found := id3v2Frames[id]; found {
+			info.setTag(key, decodeId3Text(tag[headerLen:headerLen+frameSize]))
+		}
+		tag = tag[headerLen+frameSize:]
+	}
+	return
+}
+
+// decodeId3Text decodes text frame content. First byte selects encoding: 0 ISO-8859-1, 1 UTF-16 with BOM,
+// 2 UTF-16BE, 3 UTF-8. Only first value of multi value frames is returned.
+func decodeId3Text(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	encoding, data := data[0], data[1:]
+	var text string
+	switch encoding {
+	case 1, 2:
+		var order binary.ByteOrder = binary.BigEndian
+		if encoding == 1 && len(data) >= 2 {
+			if data[0] == 0xff && data[1] == 0xfe {
+				order = binary.LittleEndian
+			}
+			data = data[2:]
+		}
+		units := make([]uint16, 0, len(data)/2)
+		for i := 0; i+1 < len(data); i += 2 {
+			units = append(units, order.Uint16(data[i:]))
+		}
+		text = string(utf16.Decode(units))
+	case 3:
+		text = string(data)
+	default:
+		text = latin1(data)
+	}
+	if idx := strings.IndexByte(text, 0); idx >= 0 {
+		text = text[:idx]
+	}
+	return strings.TrimSpace(text)
+}
+
+func latin1(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// readId3v1 fills tags missing in ID3v2 from ID3v1 tag placed at the end of file
+func readId3v1(tag []byte, info *Info) {
+	field := func(from, to int) string {
+		return strings.TrimSpace(strings.TrimRight(latin1(tag[from:to]), "\x00"))
+	}
+	fields := map[string]string{
+		TagTitle:  field(3, 33),
+		TagArtist: field(33, 63),
+		TagAlbum:  field(63, 93),
+		TagYear:   field(93, 97),
+	}
+	if tag[125] == 0 && tag[126] != 0 {
+		fields[TagTrack] = strconv.Itoa(int(tag[126]))
+	}
+	for key, value := range fields {
+		if _, found := info.Tags[key]; !found {
+			info.setTag(key, value)
+		}
+	}
+}
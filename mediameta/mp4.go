@@ -0,0 +1,167 @@
+package mediameta
+
+import (
+	"encoding/binary"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var mp4ItemTags = map[string]string{
+	"\xa9nam": TagTitle,
+	"\xa9ART": TagArtist,
+	"\xa9alb": TagAlbum,
+	"\xa9day": TagYear,
+	"\xa9gen": TagGenre,
+}
+
+// mp4 boxes which children are walked. meta is a full box so its children start after version and flags.
+var mp4Containers = map[string]int{
+	"moov": 0, "trak": 0, "mdia": 0, "minf": 0, "stbl": 0, "udta": 0, "ilst": 0, "meta": 4,
+}
+
+// maxMp4Nesting limits depth of walked container boxes. Real files nest them only a few levels deep, crafted ones
+// could otherwise make walk recurse once per 8 bytes of file.
+const maxMp4Nesting = 32
+
+var errMp4Nesting = errors.New("mp4 boxes nested too deep")
+
+type mp4Box struct {
+	kind         string
+	offset, size int64
+	headerSize   int64
+}
+
+// mp4Boxes lists boxes in range [from, to)
+func mp4Boxes(r io.ReaderAt, from, to int64) (boxes []mp4Box, err error) {
+	for offset := from; offset+8 <= to; {
+		var header []byte
+		if header, err = readAt(r, offset, 16); err != nil && err != io.EOF {
+			return
+		}
+		err = nil
+		box := mp4Box{kind: string(header[4:8]), offset: offset, size: int64(binary.BigEndian.Uint32(header)), headerSize: 8}
+		switch {
+		case box.size == 1 && len(header) >= 16:
+			box.size = int64(binary.BigEndian.Uint64(header[8:]))
+			box.headerSize = 16
+		case box.size == 0:
+			box.size = to - offset
+		}
+		if box.size < box.headerSize || offset+box.size > to {
+			return boxes, errors.New("invalid box size: " + box.kind)
+		}
+		boxes = append(boxes, box)
+		offset += box.size
+	}
+	return
+}
+
+func readMp4(r io.ReaderAt, size int64, info *Info) (err error) {
+	var mdatSize int64
+	var walk func(from, to int64, handler string, depth int) error
+	walk = func(from, to int64, handler string, depth int) (err error) {
+		if depth > maxMp4Nesting {
+			return errMp4Nesting
+		}
+		var boxes []mp4Box
+		if boxes, err = mp4Boxes(r, from, to); err != nil {
+			return
+		}
+		for _, box := range boxes {
+			body := box.offset + box.headerSize
+			if skip, isContainer := mp4Containers[box.kind]; isContainer {
+				if box.kind == "trak" {
+					handler = ""
+				}
+				if err = walk(body+int64(skip), box.offset+box.size, handler, depth+1); err != nil {
+					return
+				}
+				continue
+			}
+			var data []byte
+			switch box.kind {
+			case "mdat":
+				mdatSize += box.size - box.headerSize
+			case "mvhd":
+				if data, err = readAt(r, body, 32); err != nil {
+					return errors.Wrap(err, "mvhd")
+				}
+				info.Duration = mp4Duration(data)
+			case "hdlr":
+				if data, err = readAt(r, body, 12); err != nil {
+					return errors.Wrap(err, "hdlr")
+				}
+				handler = string(data[8:12])
+			case "mp4a", "alac":
+				// sample entry is read only for audio tracks, video ones have different layout
+				if data, err = readAt(r, body, 28); err != nil {
+					return errors.Wrap(err, "sample entry")
+				}
+				info.Channels = int(binary.BigEndian.Uint16(data[16:]))
+				info.SampleRate = int(binary.BigEndian.Uint32(data[24:]) >> 16)
+			case "stsd":
+				if handler == "soun" {
+					// stsd is a full box with entry count before sample entries
+					if err = walk(body+8, box.offset+box.size, handler, depth+1); err != nil {
+						return
+					}
+				}
+			default:
+				if key, found := mp4ItemTags[box.kind]; found || box.kind == "trkn" {
+					if data, err = mp4ItemData(r, body, box.offset+box.size); err != nil {
+						return
+					}
+					if box.kind == "trkn" {
+						if len(data) >= 4 {
+							info.setTag(TagTrack, strconv.Itoa(int(binary.BigEndian.Uint16(data[2:]))))
+						}
+					} else {
+						info.setTag(key, string(data))
+					}
+				}
+			}
+		}
+		return
+	}
+	if err = walk(0, size, "", 0); err != nil {
+		return
+	}
+	if mdatSize == 0 {
+		mdatSize = size
+	}
+	info.Bitrate = bitrateFromSize(mdatSize, info.Duration)
+	return
+}
+
+// mp4Duration reads duration from mvhd full box body
+func mp4Duration(data []byte) time.Duration {
+	be := binary.BigEndian
+	var timescale, duration uint64
+	if data[0] == 1 {
+		timescale, duration = uint64(be.Uint32(data[20:])), be.Uint64(data[24:])
+	} else {
+		timescale, duration = uint64(be.Uint32(data[12:])), uint64(be.Uint32(data[16:]))
+	}
+	if timescale == 0 {
+		return 0
+	}
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second))
+}
+
+// mp4ItemData returns value of data box inside ilst item
+func mp4ItemData(r io.ReaderAt, from, to int64) (data []byte, err error) {
+	var boxes []mp4Box
+	if boxes, err = mp4Boxes(r, from, to); err != nil {
+		return
+	}
+	for _, box := range boxes {
+		if box.kind == "data" && box.size >= box.headerSize+8 {
+			// data box starts with type indicator and locale
+			return readAt(r, box.offset+box.headerSize+8, int(box.size-box.headerSize-8))
+		}
+	}
+	return nil, nil
+}
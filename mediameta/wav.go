@@ -0,0 +1,85 @@
+package mediameta
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var wavInfoTags = map[string]string{
+	"INAM": TagTitle,
+	"IART": TagArtist,
+	"IPRD": TagAlbum,
+	"ICRD": TagYear,
+	"IGNR": TagGenre,
+	"ITRK": TagTrack,
+}
+
+// readWav walks RIFF chunks reading format, data size and LIST/INFO tags
+func readWav(r io.ReaderAt, size int64, info *Info) (err error) {
+	var (
+		byteRate int
+		dataSize int64
+	)
+	for offset := int64(12); offset+8 <= size; {
+		var header []byte
+		if header, err = readAt(r, offset, 8); err != nil {
+			return
+		}
+		chunkID := string(header[:4])
+		chunkSize := int64(binary.LittleEndian.Uint32(header[4:]))
+		switch chunkID {
+		case "fmt ":
+			var format []byte
+			if format, err = readAt(r, offset+8, 16); err != nil {
+				return errors.Wrap(err, "fmt chunk")
+			}
+			info.Channels = int(binary.LittleEndian.Uint16(format[2:]))
+			info.SampleRate = int(binary.LittleEndian.Uint32(format[4:]))
+			byteRate = int(binary.LittleEndian.Uint32(format[8:]))
+		case "data":
+			dataSize = chunkSize
+			if offset+8+dataSize > size {
+				dataSize = size - offset - 8
+			}
+		case "LIST":
+			var list []byte
+			// truncated LIST chunk still holds readable tags
+			listSize := chunkSize
+			if offset+8+listSize > size {
+				listSize = size - offset - 8
+			}
+			if list, err = readAt(r, offset+8, int(listSize)); err != nil {
+				return errors.Wrap(err, "LIST chunk")
+			}
+			readWavInfo(list, info)
+		}
+		// chunks are word aligned
+		offset += 8 + chunkSize + chunkSize%2
+	}
+	if byteRate == 0 {
+		return errors.New("fmt chunk not found")
+	}
+	info.Bitrate = byteRate * 8
+	info.Duration = durationFromRate(dataSize, byteRate)
+	return
+}
+
+func readWavInfo(list []byte, info *Info) {
+	if len(list) < 4 || string(list[:4]) != "INFO" {
+		return
+	}
+	for pos := 4; pos+8 <= len(list); {
+		id := string(list[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(list[pos+4:]))
+		if pos+8+size > len(list) {
+			return
+		}
+		if key, found := wavInfoTags[id]; found {
+			info.setTag(key, strings.TrimSpace(strings.TrimRight(string(list[pos+8:pos+8+size]), "\x00")))
+		}
+		pos += 8 + size + size%2
+	}
+}
@@ -0,0 +1,90 @@
+package mimechecker
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Executable media types reported by Executable checker
+const (
+	MimeELF   = "application/x-executable"
+	MimePE    = "application/vnd.microsoft.portable-executable"
+	MimeMachO = "application/x-mach-binary"
+)
+
+// Executable recognizes ELF, PE and Mach-O binaries by magic numbers. net/http sniffing reports them as
+// application/octet-stream.
+type Executable struct{}
+
+func (c Executable) TypeByFile(path string) (m string, err error) {
+	var result *Result
+	if result, err = c.Detect(path); err != nil {
+		return
+	}
+	return result.MediaType, nil
+}
+
+func (Executable) Detect(path string) (result *Result, err error) {
+	var handle *os.File
+	if handle, err = os.Open(path); err != nil {
+		err = errors.Wrap(err, "os.Open")
+		return
+	}
+	defer handle.Close()
+	header := make([]byte, 64)
+	var n int
+	if n, err = io.ReadFull(handle, header); err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		err = errors.Wrap(err, "read")
+		return
+	}
+	err = nil
+	header = header[:n]
+	m := executableType(header)
+	if m == MimePE && !hasPESignature(handle, header) {
+		m = ""
+	}
+	result = ParseResult(m, "executable", ConfidenceContent)
+	result.Source = SourceContent
+	return
+}
+
+func executableType(header []byte) string {
+	switch {
+	case bytes.HasPrefix(header, []byte("\x7fELF")):
+		return MimeELF
+	case bytes.HasPrefix(header, []byte("MZ")):
+		return MimePE
+	case len(header) < 8:
+		return ""
+	}
+	switch binary.BigEndian.Uint32(header) {
+	case 0xfeedface, 0xfeedfacf, 0xcefaedfe, 0xcffaedfe:
+		return MimeMachO
+	case 0xcafebabe:
+		// universal binaries share magic with Java class files, which store version instead of small arch count
+		if binary.BigEndian.Uint32(header[4:]) < 20 {
+			return MimeMachO
+		}
+	}
+	return ""
+}
+
+// hasPESignature checks "PE\0\0" at offset stored in DOS header, plain "MZ" prefix is too weak
+func hasPESignature(r io.ReaderAt, header []byte) bool {
+	if len(header) < 64 {
+		return false
+	}
+	signature := make([]byte, 4)
+	if _, err := r.ReadAt(signature, int64(binary.LittleEndian.Uint32(header[60:]))); err != nil {
+		return false
+	}
+	return bytes.Equal(signature, []byte("PE\x00\x00"))
+}
+
+func NewExecutable() *Executable {
+	return &Executable{}
+}
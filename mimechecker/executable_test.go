@@ -0,0 +1,82 @@
+package mimechecker
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutable_Detect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "executable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	pe := make([]byte, 128)
+	copy(pe, "MZ")
+	binary.LittleEndian.PutUint32(pe[60:], 64)
+	copy(pe[64:], "PE\x00\x00")
+	fatMachO := make([]byte, 64)
+	binary.BigEndian.PutUint32(fatMachO, 0xcafebabe)
+	binary.BigEndian.PutUint32(fatMachO[4:], 2)
+	javaClass := make([]byte, 64)
+	binary.BigEndian.PutUint32(javaClass, 0xcafebabe)
+	binary.BigEndian.PutUint32(javaClass[4:], 52)
+	testExpectations := []struct {
+		name    string
+		content []byte
+		mime    string
+	}{
+		{"elf", append([]byte("\x7fELF\x02\x01\x01"), make([]byte, 57)...), MimeELF},
+		{"pe", pe, MimePE},
+		{"dos-stub-only", append([]byte("MZ"), make([]byte, 62)...), ""},
+		{"macho-64", []byte{0xcf, 0xfa, 0xed, 0xfe, 7, 0, 0, 1}, MimeMachO},
+		{"macho-fat", fatMachO, MimeMachO},
+		{"java-class", javaClass, ""},
+		{"text", []byte("plain text"), ""},
+		{"empty", nil, ""},
+	}
+	checker := NewExecutable()
+	for _, expectation := range testExpectations {
+		t.Run(expectation.name, func(t *testing.T) {
+			path := filepath.Join(dir, expectation.name)
+			if err := ioutil.WriteFile(path, expectation.content, 0644); err != nil {
+				t.Fatal(err)
+			}
+			result, err := checker.Detect(path)
+			assert.NoError(t, err)
+			assert.Equal(t, expectation.mime, result.MediaType)
+			assert.Equal(t, SourceContent, result.Source)
+		})
+	}
+}
+
+func TestFamilies_Equivalent(t *testing.T) {
+	testExpectations := []struct {
+		a, b       string
+		equivalent bool
+	}{
+		{"image/png", "image/jpeg", true},
+		{"audio/mpeg", "image/gif", false},
+		{"audio/mpeg", "video/mp4", true},
+		{"text/plain; charset=utf-8", "application/json", true},
+		{"application/zip", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", true},
+		{"image/jpeg", MimeELF, false},
+		{"application/pdf", "application/pdf", true},
+		{"application/pdf", "application/x-foo", false},
+	}
+	for _, expectation := range testExpectations {
+		t.Run(expectation.a+" "+expectation.b, func(t *testing.T) {
+			assert.Equal(t, expectation.equivalent, DefaultFamilies.Equivalent(expectation.a, expectation.b))
+		})
+	}
+	t.Run("Custom", func(t *testing.T) {
+		families := Families{"image/*": "media", "audio/*": "media"}
+		assert.True(t, families.Equivalent("audio/mpeg", "image/gif"))
+		assert.False(t, families.Equivalent("audio/mpeg", "video/mp4"))
+	})
+}
@@ -0,0 +1,55 @@
+package mimechecker
+
+import "strings"
+
+// Families maps media types to family names. Types of same family are treated as equivalent when comparing extension
+// and content of file. Keys are either exact types ("application/json") or type wildcards ("image/*"). Types not
+// present in map form their own family.
+type Families map[string]string
+
+// DefaultFamilies groups types that content sniffing and extension lookup commonly report differently for same file
+var DefaultFamilies = Families{
+	"text/*":                   "text",
+	"application/json":         "text",
+	"application/xml":          "text",
+	"application/javascript":   "text",
+	"application/x-yaml":       "text",
+	"application/yaml":         "text",
+	"image/*":                  "image",
+	"audio/*":                  "media",
+	"video/*":                  "media",
+	"application/ogg":          "media",
+	"application/zip":          "archive",
+	"application/java-archive": "archive",
+	"application/epub+zip":     "archive",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   "archive",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         "archive",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": "archive",
+	"application/vnd.oasis.opendocument.text":                                   "archive",
+	"application/vnd.oasis.opendocument.spreadsheet":                            "archive",
+	"application/vnd.oasis.opendocument.presentation":                           "archive",
+	"application/x-gzip":          "gzip",
+	"application/gzip":            "gzip",
+	MimeELF:                       "executable",
+	MimePE:                        "executable",
+	MimeMachO:                     "executable",
+	"application/x-msdownload":    "executable",
+	"application/x-msdos-program": "executable",
+}
+
+// Family returns family of media type. Parameters are ignored.
+func (f Families) Family(mediaType string) string {
+	parsed := ParseResult(mediaType, "", 0)
+	if family, found := f[parsed.MediaType]; found {
+		return family
+	}
+	if family, found := f[parsed.Type()+"/*"]; found {
+		return family
+	}
+	return parsed.MediaType
+}
+
+// Equivalent checks if both media types belong to same family
+func (f Families) Equivalent(a, b string) bool {
+	return strings.EqualFold(f.Family(a), f.Family(b))
+}
@@ -33,6 +33,20 @@ func (*goHttp) TypeByFile(path string) (m string, err error) {
 	return
 }
 
+// Detect sniffs content of file. Plain text results are reported with lower confidence as any text matches them.
+func (c *goHttp) Detect(path string) (result *Result, err error) {
+	var m string
+	if m, err = c.TypeByFile(path); err != nil {
+		return
+	}
+	result = ParseResult(m, "http", ConfidenceContent)
+	result.Source = SourceContent
+	if result.MediaType == "text/plain" {
+		result.Confidence = ConfidenceGeneric
+	}
+	return
+}
+
 func NewGoHttp() *goHttp {
 	return &goHttp{}
-}
\ No newline at end of file
+}
@@ -11,6 +11,14 @@ func (GoMime) TypeByFile(path string) (string, error) {
 	return mime.TypeByExtension(filepath.Ext(path)), nil
 }
 
+// Detect guesses type from file extension
+func (c GoMime) Detect(path string) (*Result, error) {
+	m, _ := c.TypeByFile(path)
+	result := ParseResult(m, "extension", ConfidenceExtension)
+	result.Source = SourceExtension
+	return result, nil
+}
+
 func NewGoMime() *GoMime {
 	return &GoMime{}
-}
\ No newline at end of file
+}
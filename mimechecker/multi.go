@@ -2,8 +2,29 @@ package mimechecker
 
 import "errors"
 
+// Strategy decides how Multi combines results of its checkers
+type Strategy string
+
+const (
+	// FirstWins returns first non empty result. Remaining checkers aren't consulted.
+	FirstWins Strategy = "first-wins"
+	// ContentOverExtension prefers content sniffing results over extension ones
+	ContentOverExtension Strategy = "content-over-extension"
+	// ExtensionOverContent prefers extension results over content sniffing ones
+	ExtensionOverContent Strategy = "extension-over-content"
+	// MajorityVote returns type reported by most checkers. Ties are resolved by summed confidence, then by order of
+	// checkers. Confidence of result is scaled by share of votes.
+	MajorityVote Strategy = "majority-vote"
+	// MismatchReport works like ContentOverExtension and sets Result.Mismatch if content and extension checkers
+	// report different types
+	MismatchReport Strategy = "mismatch-report"
+)
+
+var ErrInvalidStrategy = errors.New("invalid multi mimechecker strategy")
+
 type Multi struct {
 	checkers []Checker
+	strategy Strategy
 }
 
 func (c Multi) TypeByFile(path string) (m string, err error) {
@@ -12,6 +33,13 @@ func (c Multi) TypeByFile(path string) (m string, err error) {
 			err = errors.New("multi mimechecker: " + err.Error())
 		}
 	}()
+	if !c.firstWins() {
+		var result *Result
+		if result, err = c.detectAll(path); err == nil {
+			m = result.String()
+		}
+		return
+	}
 	for _, checker := range c.checkers {
 		m, err = checker.TypeByFile(path)
 		if m != "" || err != nil {
@@ -21,7 +49,113 @@ func (c Multi) TypeByFile(path string) (m string, err error) {
 	return
 }
 
+// Detect combines results of checkers using strategy. Result.Checker names checker that produced it.
+func (c Multi) Detect(path string) (result *Result, err error) {
+	defer func() {
+		if err != nil {
+			err = errors.New("multi mimechecker: " + err.Error())
+		}
+	}()
+	if !c.firstWins() {
+		return c.detectAll(path)
+	}
+	for _, checker := range c.checkers {
+		if result, err = AsDetector(checker).Detect(path); err != nil || result != nil && result.MediaType != "" {
+			return
+		}
+	}
+	if result == nil {
+		result = &Result{}
+	}
+	return
+}
+
+func (c Multi) firstWins() bool {
+	return c.strategy == FirstWins || c.strategy == ""
+}
+
+func (c Multi) detectAll(path string) (result *Result, err error) {
+	var candidates []*Result
+	for _, checker := range c.checkers {
+		var checkerResult *Result
+		if checkerResult, err = AsDetector(checker).Detect(path); err != nil {
+			return
+		}
+		if checkerResult != nil && checkerResult.MediaType != "" {
+			candidates = append(candidates, checkerResult)
+		}
+	}
+	if len(candidates) == 0 {
+		return &Result{}, nil
+	}
+	var chosen Result
+	switch c.strategy {
+	case ContentOverExtension, MismatchReport:
+		chosen = *preferSource(candidates, SourceContent, SourceExtension)
+	case ExtensionOverContent:
+		chosen = *preferSource(candidates, SourceExtension, SourceContent)
+	case MajorityVote:
+		chosen = majorityVote(candidates)
+	}
+	if c.strategy == MismatchReport {
+		content := firstBySource(candidates, SourceContent)
+		extension := firstBySource(candidates, SourceExtension)
+		chosen.Mismatch = content != nil && extension != nil && content.MediaType != extension.MediaType
+	}
+	chosen.Candidates = candidates
+	return &chosen, nil
+}
+
+func firstBySource(candidates []*Result, source Source) *Result {
+	for _, candidate := range candidates {
+		if candidate.Source == source {
+			return candidate
+		}
+	}
+	return nil
+}
+
+func preferSource(candidates []*Result, preferred, fallback Source) *Result {
+	if result := firstBySource(candidates, preferred); result != nil {
+		return result
+	}
+	if result := firstBySource(candidates, fallback); result != nil {
+		return result
+	}
+	return candidates[0]
+}
+
+func majorityVote(candidates []*Result) Result {
+	votes := map[string]int{}
+	confidence := map[string]float64{}
+	for _, candidate := range candidates {
+		votes[candidate.MediaType]++
+		confidence[candidate.MediaType] += candidate.Confidence
+	}
+	var winner *Result
+	for _, candidate := range candidates {
+		if winner == nil || votes[candidate.MediaType] > votes[winner.MediaType] ||
+			votes[candidate.MediaType] == votes[winner.MediaType] &&
+				confidence[candidate.MediaType] > confidence[winner.MediaType] {
+			winner = candidate
+		}
+	}
+	chosen := *winner
+	chosen.Confidence = confidence[winner.MediaType] / float64(len(candidates))
+	return chosen
+}
+
 func NewMulti(checkers ...Checker) *Multi {
-	return &Multi{checkers}
+	return &Multi{checkers, FirstWins}
 }
 
+// NewMultiWithStrategy creates Multi that combines results of checkers using given strategy
+func NewMultiWithStrategy(strategy Strategy, checkers ...Checker) (multi *Multi, err error) {
+	switch strategy {
+	case FirstWins, ContentOverExtension, ExtensionOverContent, MajorityVote, MismatchReport:
+		multi = &Multi{checkers, strategy}
+	default:
+		err = ErrInvalidStrategy
+	}
+	return
+}
@@ -36,3 +36,111 @@ func TestMulti_ByPath(t *testing.T) {
 		assert.Equal(t, "multi mimechecker: mock-1-error", err.Error())
 	})
 }
+
+func TestMulti_Detect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mock1 := mock_mimechecker.NewMockChecker(ctrl)
+	multi := NewMulti(mock1, NewGoMime())
+	t.Run("WrapPlainChecker", func(t *testing.T) {
+		mock1.EXPECT().TypeByFile("test.path").Return("mock-1/mime; a=b", nil)
+		result, _ := multi.Detect("test.path")
+		assert.Equal(t, "mock-1/mime", result.MediaType)
+		assert.Equal(t, map[string]string{"a": "b"}, result.Params)
+		assert.Equal(t, "*mock_mimechecker.MockChecker", result.Checker)
+		assert.Equal(t, ConfidenceUnknown, result.Confidence)
+	})
+	t.Run("ReportCheckerThatFoundMime", func(t *testing.T) {
+		mock1.EXPECT().TypeByFile("test.png").Return("", nil)
+		result, _ := multi.Detect("test.png")
+		assert.Equal(t, "image/png", result.MediaType)
+		assert.Equal(t, "extension", result.Checker)
+	})
+	t.Run("EmptyResult", func(t *testing.T) {
+		mock1.EXPECT().TypeByFile("test").Return("", nil)
+		result, err := multi.Detect("test")
+		assert.NoError(t, err)
+		assert.Equal(t, "", result.MediaType)
+	})
+	t.Run("ReturnWrappedErr", func(t *testing.T) {
+		mock1.EXPECT().TypeByFile("test").Return("", errors.New("mock-1-error"))
+		_, err := multi.Detect("test")
+		assert.Equal(t, "multi mimechecker: mock-1-error", err.Error())
+	})
+}
+
+// nilDetector reports no result without error
+type nilDetector struct{}
+
+func (nilDetector) TypeByFile(path string) (string, error) {
+	return "", nil
+}
+
+func (nilDetector) Detect(path string) (*Result, error) {
+	return nil, nil
+}
+
+func TestMulti_NilResult(t *testing.T) {
+	for _, strategy := range []Strategy{FirstWins, ContentOverExtension, MajorityVote} {
+		multi, _ := NewMultiWithStrategy(strategy, nilDetector{}, NewGoMime())
+		result, err := multi.Detect("test.png")
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", result.MediaType)
+		multi, _ = NewMultiWithStrategy(strategy, nilDetector{})
+		result, err = multi.Detect("test.png")
+		assert.NoError(t, err)
+		assert.Equal(t, "", result.MediaType)
+	}
+}
+
+func TestMulti_Strategies(t *testing.T) {
+	path := "../test_files/mime/image-gif-fake-audio.mp3"
+	testExpectations := []struct {
+		strategy  Strategy
+		mediaType string
+		checker   string
+		mismatch  bool
+	}{
+		{FirstWins, "audio/mpeg", "extension", false},
+		{ContentOverExtension, "image/gif", "http", false},
+		{ExtensionOverContent, "audio/mpeg", "extension", false},
+		{MismatchReport, "image/gif", "http", true},
+	}
+	for _, expectation := range testExpectations {
+		t.Run(string(expectation.strategy), func(t *testing.T) {
+			multi, err := NewMultiWithStrategy(expectation.strategy, NewGoMime(), NewGoHttp())
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := multi.Detect(path)
+			assert.NoError(t, err)
+			assert.Equal(t, expectation.mediaType, result.MediaType)
+			assert.Equal(t, expectation.mismatch, result.Mismatch)
+			assert.Equal(t, expectation.checker, result.Checker)
+			if expectation.strategy != FirstWins {
+				assert.Len(t, result.Candidates, 2)
+			}
+		})
+	}
+	t.Run("MismatchReportAgreement", func(t *testing.T) {
+		multi, _ := NewMultiWithStrategy(MismatchReport, NewGoHttp(), NewGoMime())
+		result, _ := multi.Detect("../test_files/mime/image.png")
+		assert.Equal(t, "image/png", result.MediaType)
+		assert.False(t, result.Mismatch)
+	})
+	t.Run("MajorityVote", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mock1 := mock_mimechecker.NewMockChecker(ctrl)
+		mock1.EXPECT().TypeByFile(path).Return("image/gif", nil)
+		multi, _ := NewMultiWithStrategy(MajorityVote, NewGoMime(), NewGoHttp(), mock1)
+		result, _ := multi.Detect(path)
+		assert.Equal(t, "image/gif", result.MediaType)
+		assert.Equal(t, "http", result.Checker)
+		assert.InDelta(t, (ConfidenceContent+ConfidenceUnknown)/3, result.Confidence, 0.0001)
+	})
+	t.Run("InvalidStrategy", func(t *testing.T) {
+		_, err := NewMultiWithStrategy("unknown")
+		assert.Equal(t, ErrInvalidStrategy, err)
+	})
+}
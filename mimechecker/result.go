@@ -0,0 +1,124 @@
+package mimechecker
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// Confidence levels reported by bundled checkers. Content sniffing is trusted more than file extension, generic
+// types (like text/plain returned for any text) are trusted less than specific ones.
+const (
+	ConfidenceUnknown   = 0.5
+	ConfidenceExtension = 0.6
+	ConfidenceGeneric   = 0.4
+	ConfidenceContent   = 0.9
+)
+
+// Source tells what detection was based on
+type Source string
+
+const (
+	SourceUnknown   Source = ""
+	SourceContent   Source = "content"
+	SourceExtension Source = "extension"
+)
+
+// Result is MIME type detected for file
+type Result struct {
+	// MediaType is lower cased type without parameters, e.g. "text/plain". Empty if type is unknown
+	MediaType string
+	// Params holds parameters like charset. Keys are lower cased
+	Params map[string]string
+	// Checker is name of checker that produced result
+	Checker string
+	// Confidence is checker's certainty in range 0-1
+	Confidence float64
+	// Source tells if type was sniffed from content or guessed from file name
+	Source Source
+	// Mismatch is set by Multi with MismatchReport strategy when content and extension checkers disagree
+	Mismatch bool
+	// Candidates holds non empty results of all checkers consulted by Multi (strategies other than FirstWins)
+	Candidates []*Result
+}
+
+// Detector is Checker that reports detection details
+type Detector interface {
+	Detect(path string) (*Result, error)
+}
+
+// ParseResult parses MIME value like "text/plain; charset=utf-8". Values that can't be parsed are kept in MediaType
+// as is.
+func ParseResult(value, checker string, confidence float64) *Result {
+	result := &Result{Checker: checker, Confidence: confidence}
+	if value == "" {
+		return result
+	}
+	mediaType, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		result.MediaType = strings.ToLower(strings.TrimSpace(value))
+		return result
+	}
+	result.MediaType = mediaType
+	if len(params) > 0 {
+		result.Params = params
+	}
+	return result
+}
+
+// String formats result back to MIME value with parameters
+func (r *Result) String() string {
+	if r.MediaType == "" {
+		return ""
+	}
+	if formatted := mime.FormatMediaType(r.MediaType, r.Params); formatted != "" {
+		return formatted
+	}
+	return r.MediaType
+}
+
+// Type returns top level type, e.g. "image" for "image/png"
+func (r *Result) Type() string {
+	return strings.SplitN(r.MediaType, "/", 2)[0]
+}
+
+// Subtype returns subtype, e.g. "png" for "image/png"
+func (r *Result) Subtype() string {
+	parts := strings.SplitN(r.MediaType, "/", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// Matches checks media type against pattern. Pattern may use wildcard in place of type or subtype, e.g. "image/*".
+// Parameters of pattern (if any) are ignored.
+func (r *Result) Matches(pattern string) bool {
+	if r.MediaType == "" {
+		return false
+	}
+	patternType := ParseResult(pattern, "", 0)
+	pt, ps := patternType.Type(), patternType.Subtype()
+	return (pt == "*" || pt == r.Type()) && (ps == "*" || ps == r.Subtype())
+}
+
+// AsDetector returns checker as Detector. Checkers that don't implement Detector are wrapped and report
+// ConfidenceUnknown.
+func AsDetector(checker Checker) Detector {
+	if detector, ok := checker.(Detector); ok {
+		return detector
+	}
+	return checkerDetector{checker}
+}
+
+type checkerDetector struct {
+	checker Checker
+}
+
+func (d checkerDetector) Detect(path string) (result *Result, err error) {
+	var m string
+	if m, err = d.checker.TypeByFile(path); err != nil {
+		return
+	}
+	return ParseResult(m, fmt.Sprintf("%T", d.checker), ConfidenceUnknown), nil
+}
@@ -0,0 +1,70 @@
+package mimechecker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResult(t *testing.T) {
+	testExpectations := []struct {
+		value     string
+		mediaType string
+		params    map[string]string
+		formatted string
+	}{
+		{"", "", nil, ""},
+		{"image/png", "image/png", nil, "image/png"},
+		{"Text/Plain; Charset=utf-8", "text/plain", map[string]string{"charset": "utf-8"}, "text/plain; charset=utf-8"},
+		{"not a mime", "not a mime", nil, "not a mime"},
+	}
+	for _, expectation := range testExpectations {
+		t.Run(expectation.value, func(t *testing.T) {
+			result := ParseResult(expectation.value, "test", ConfidenceUnknown)
+			assert.Equal(t, expectation.mediaType, result.MediaType)
+			assert.Equal(t, expectation.params, result.Params)
+			assert.Equal(t, expectation.formatted, result.String())
+			assert.Equal(t, "test", result.Checker)
+		})
+	}
+}
+
+func TestResult_Matches(t *testing.T) {
+	testExpectations := []struct {
+		value   string
+		pattern string
+		matches bool
+	}{
+		{"image/png", "image/png", true},
+		{"image/png", "image/*", true},
+		{"image/png", "*/*", true},
+		{"image/png", "*/png", true},
+		{"image/png", "image/jpeg", false},
+		{"image/png", "audio/*", false},
+		{"text/plain; charset=utf-8", "text/plain", true},
+		{"", "*/*", false},
+	}
+	for _, expectation := range testExpectations {
+		t.Run(expectation.value+" "+expectation.pattern, func(t *testing.T) {
+			assert.Equal(t, expectation.matches, ParseResult(expectation.value, "", 0).Matches(expectation.pattern))
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Run("GoMime", func(t *testing.T) {
+		result, _ := NewGoMime().Detect("image.png")
+		assert.Equal(t, &Result{MediaType: "image/png", Checker: "extension", Confidence: ConfidenceExtension,
+			Source: SourceExtension}, result)
+	})
+	t.Run("GoHttp", func(t *testing.T) {
+		result, _ := NewGoHttp().Detect("../test_files/mime/text-with-bom.txt")
+		assert.Equal(t, &Result{
+			MediaType:  "text/plain",
+			Params:     map[string]string{"charset": "utf-8"},
+			Checker:    "http",
+			Confidence: ConfidenceGeneric,
+			Source:     SourceContent,
+		}, result)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 	"os"
 	"github.com/duffpl/go-finder/file"
+	"github.com/duffpl/go-finder/mimechecker"
 )
 
 type mockFileInfoEx struct {
@@ -12,6 +13,9 @@ type mockFileInfoEx struct {
 	mime     string
 	abs      string
 	checksum []byte
+	attrs    map[string]interface{}
+	stat     *file.Stat
+	hashed   bool
 }
 
 func (m *mockFileInfoEx) Name() string {
@@ -46,10 +50,44 @@ func (m *mockFileInfoEx) Checksum() (cs []byte, err error) {
 	return m.checksum, nil
 }
 
+// CountedChecksum pretends whole file is read on first call
+func (m *mockFileInfoEx) CountedChecksum(counter func(n int64)) (cs []byte, cached bool, err error) {
+	if cached = m.hashed; !cached && counter != nil {
+		counter(m.size)
+	}
+	m.hashed = true
+	return m.checksum, cached, nil
+}
+
 func (m *mockFileInfoEx) Mime() (r string, err error) {
 	return m.mime, nil
 }
 
+func (m *mockFileInfoEx) MimeResult() (r *mimechecker.Result, err error) {
+	return mimechecker.ParseResult(m.mime, "mock", mimechecker.ConfidenceUnknown), nil
+}
+
+func (m *mockFileInfoEx) Stat() (stat file.Stat, ok bool) {
+	if m.stat == nil {
+		return
+	}
+	return *m.stat, true
+}
+
+func (m *mockFileInfoEx) Attr(name string, cb file.AttrCallback) (v interface{}, err error) {
+	if v, found := m.attrs[name]; found || cb == nil {
+		return v, nil
+	}
+	if v, err = cb(m.abs); err != nil {
+		return
+	}
+	if m.attrs == nil {
+		m.attrs = map[string]interface{}{}
+	}
+	m.attrs[name] = v
+	return
+}
+
 func newMockGlobFunc(result []file.FileInfoEx) FileInfoExGlobFunc {
 	return func(pattern string) ([]file.FileInfoEx, error) {
 		return result, nil
@@ -0,0 +1,188 @@
+package finder
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/duffpl/go-finder/file"
+)
+
+// replanInterval is number of checked files after which adaptive planner reorders filters
+const replanInterval = 64
+
+// priorLatency is assumed latency of filter of given cost before it's measured. It's blended with measurements, so
+// rarely reached filters are still ordered reasonably.
+var priorLatency = map[Cost]time.Duration{
+	CostName:     100 * time.Nanosecond,
+	CostMetadata: time.Microsecond,
+	CostHead:     100 * time.Microsecond,
+	CostContent:  10 * time.Millisecond,
+	CostExternal: 50 * time.Millisecond,
+}
+
+// FilterStats are statistics of single filter collected by adaptive planner
+type FilterStats struct {
+	Name    string
+	Cost    Cost
+	Checked int64
+	Passed  int64
+	// Errors is number of checks that failed with error. Such files are rejected.
+	Errors int64
+	// Total is time spent in filter
+	Total time.Duration
+}
+
+// Rejected returns number of files rejected by filter
+func (s FilterStats) Rejected() int64 {
+	return s.Checked - s.Passed
+}
+
+// PassRate returns fraction of checked files that passed filter
+func (s FilterStats) PassRate() float64 {
+	if s.Checked == 0 {
+		return 0
+	}
+	return float64(s.Passed) / float64(s.Checked)
+}
+
+// AvgLatency returns average time of single check
+func (s FilterStats) AvgLatency() time.Duration {
+	if s.Checked == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Checked)
+}
+
+// rank is expected cost of filter per rejected file. Checking filters by ascending rank minimizes expected cost of
+// AND chain of independent filters. Pass rate and latency are smoothed with single prior observation.
+func (s FilterStats) rank() float64 {
+	latency := float64(s.Total+priorLatency[s.Cost]) / float64(s.Checked+1)
+	passRate := float64(s.Passed+1) / float64(s.Checked+2)
+	return latency / (1 - passRate)
+}
+
+type plannedFilter struct {
+	filter  Filter
+	checked int64
+	passed  int64
+	errors  int64
+	total   int64
+}
+
+func (p *plannedFilter) stats() FilterStats {
+	return FilterStats{
+		Name:    p.filter.Name(),
+		Cost:    p.filter.Cost(),
+		Checked: atomic.LoadInt64(&p.checked),
+		Passed:  atomic.LoadInt64(&p.passed),
+		Errors:  atomic.LoadInt64(&p.errors),
+		Total:   time.Duration(atomic.LoadInt64(&p.total)),
+	}
+}
+
+// planner checks filters and collects statistics of Glob run. If adaptive, filter order is reevaluated every
+// replanInterval files using measured pass rates and latencies.
+type planner struct {
+	order    atomic.Value
+	adaptive bool
+	replan   sync.Mutex
+
+	listed        int64
+	checked       int64
+	matched       int64
+	checksumBytes int64
+	sniffBytes    int64
+	cacheHits     int64
+	cacheMisses   int64
+	duration      int64
+
+	errorsMu sync.Mutex
+	errors   map[string]int64
+}
+
+func newPlanner(filters []Filter, adaptive bool) *planner {
+	p := &planner{adaptive: adaptive, errors: map[string]int64{}}
+	order := make([]*plannedFilter, len(filters))
+	for idx, filter := range filters {
+		order[idx] = &plannedFilter{filter: filter}
+	}
+	p.order.Store(order)
+	return p
+}
+
+func (p *planner) check(input file.FileInfoEx) bool {
+	order := p.order.Load().([]*plannedFilter)
+	input = &statsFileInfo{FileInfoEx: input, plan: p}
+	matched := true
+	for _, planned := range order {
+		var err error
+		start := time.Now()
+		matched, err = planned.filter.Match(input)
+		atomic.AddInt64(&planned.total, int64(time.Since(start)))
+		atomic.AddInt64(&planned.checked, 1)
+		if err != nil {
+			atomic.AddInt64(&planned.errors, 1)
+			p.addError(err)
+			matched = false
+		}
+		if !matched {
+			break
+		}
+		atomic.AddInt64(&planned.passed, 1)
+	}
+	if matched {
+		atomic.AddInt64(&p.matched, 1)
+	}
+	if atomic.AddInt64(&p.checked, 1)%replanInterval == 0 && p.adaptive {
+		p.reorder()
+	}
+	return matched
+}
+
+func (p *planner) addError(err error) {
+	p.errorsMu.Lock()
+	p.errors[errorType(err)]++
+	p.errorsMu.Unlock()
+}
+
+func (p *planner) reorder() {
+	p.replan.Lock()
+	defer p.replan.Unlock()
+	current := p.order.Load().([]*plannedFilter)
+	ranks := make(map[*plannedFilter]float64, len(current))
+	for _, planned := range current {
+		ranks[planned] = planned.stats().rank()
+	}
+	order := append([]*plannedFilter{}, current...)
+	sort.SliceStable(order, func(i, j int) bool {
+		return ranks[order[i]] < ranks[order[j]]
+	})
+	p.order.Store(order)
+}
+
+func (p *planner) stats() (stats []FilterStats) {
+	for _, planned := range p.order.Load().([]*plannedFilter) {
+		stats = append(stats, planned.stats())
+	}
+	return
+}
+
+// Adaptive enables planner which measures pass rate and latency of every filter during Glob and reorders filters on
+// the fly, so most selective and cheapest ones run first. Result is the same as with static order by cost.
+func (f *Finder) Adaptive() *Finder {
+	f.adaptive = true
+	return f
+}
+
+// FilterStats returns statistics of filters collected during last adaptive Glob (or Glob with stats collection), in
+// order filters ended up in. It returns nil if neither Adaptive nor CollectStats was enabled.
+func (f *Finder) FilterStats() []FilterStats {
+	f.planMu.Lock()
+	defer f.planMu.Unlock()
+	if f.plan == nil {
+		return nil
+	}
+	return f.plan.stats()
+}
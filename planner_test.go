@@ -0,0 +1,49 @@
+package finder
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinder_Adaptive(t *testing.T) {
+	var items []file.FileInfoEx
+	for i := 0; i < 1000; i++ {
+		items = append(items, &mockFileInfoEx{name: fmt.Sprintf("%04d", i), size: int64(i)})
+	}
+	slowUnselective := NewFilter("slow", CostName, 0, func(fiex file.FileInfoEx) (bool, error) {
+		time.Sleep(20 * time.Microsecond)
+		return true, nil
+	})
+	selective := NewFilter("selective", CostName, 0, func(fiex file.FileInfoEx) (bool, error) {
+		return fiex.Size()%100 == 0, nil
+	})
+	sut := New().SetGlobFunc(newMockGlobFunc(items)).SetCheckerConcurrency(1).
+		Where(slowUnselective).
+		Where(selective).
+		Adaptive()
+	assert.Nil(t, sut.FilterStats())
+	result, err := sut.Glob("*")
+	assert.NoError(t, err)
+	assert.Len(t, result, 10)
+
+	stats := sut.FilterStats()
+	if assert.Len(t, stats, 2) {
+		assert.Equal(t, "selective", stats[0].Name)
+		assert.Equal(t, int64(1000), stats[0].Checked)
+		assert.InDelta(t, 0.01, stats[0].PassRate(), 0.001)
+		assert.Equal(t, "slow", stats[1].Name)
+		assert.True(t, stats[1].Checked < 200)
+		assert.True(t, stats[1].AvgLatency() >= 20*time.Microsecond)
+	}
+}
+
+func TestFilterStats_rank(t *testing.T) {
+	cheapUnselective := FilterStats{Cost: CostName, Checked: 1000, Passed: 999, Total: time.Millisecond}
+	expensiveSelective := FilterStats{Cost: CostContent, Checked: 1000, Passed: 10, Total: 100 * time.Millisecond}
+	assert.True(t, expensiveSelective.rank() < cheapUnselective.rank())
+	assert.True(t, FilterStats{Cost: CostName}.rank() < FilterStats{Cost: CostContent}.rank())
+}
@@ -0,0 +1,180 @@
+package finder
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/duffpl/go-finder/mimechecker"
+	"github.com/pkg/errors"
+)
+
+// Progress is snapshot of running Glob. Listed is known once glob function returns and nothing is reported while it's
+// listing files, so first report comes after listing is done. ETA is estimated from average time of processed entries,
+// it's zero until first entry is processed. BytesHashed counts bytes as they are read by checksum callback, checksums
+// computed before (e.g. by earlier Glob over same items) add nothing.
+type Progress struct {
+	Listed      int64
+	Processed   int64
+	Matched     int64
+	BytesHashed int64
+	Elapsed     time.Duration
+	ETA         time.Duration
+	// Done is set for last report sent after all entries are processed
+	Done bool
+}
+
+// Percent returns processed fraction of listed entries in [0, 100]
+func (p Progress) Percent() float64 {
+	if p.Listed == 0 {
+		return 100
+	}
+	return float64(p.Processed) * 100 / float64(p.Listed)
+}
+
+// OnProgress makes Glob (and everything based on GlobEach) report progress to cb every interval and once more when
+// done. Workers only update atomic counters, cb is called from separate go routine, one call at a time.
+func (f *Finder) OnProgress(interval time.Duration, cb func(progress Progress)) *Finder {
+	if f.lastErr != nil { return f }
+	if interval <= 0 {
+		f.lastErr = errors.New("progress interval must be larger than 0")
+		return f
+	}
+	f.progressInterval = interval
+	f.progressCb = cb
+	return f
+}
+
+type progressTracker struct {
+	start       time.Time
+	listed      int64
+	processed   int64
+	matched     int64
+	bytesHashed int64
+	stop        chan struct{}
+	stopped     chan struct{}
+}
+
+func startProgress(listed int, interval time.Duration, cb func(progress Progress)) *progressTracker {
+	t := &progressTracker{
+		start:   time.Now(),
+		listed:  int64(listed),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go func() {
+		defer close(t.stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				cb(t.snapshot(false))
+			case <-t.stop:
+				cb(t.snapshot(true))
+				return
+			}
+		}
+	}()
+	return t
+}
+
+func (t *progressTracker) snapshot(done bool) (progress Progress) {
+	progress = Progress{
+		Listed:      t.listed,
+		Processed:   atomic.LoadInt64(&t.processed),
+		Matched:     atomic.LoadInt64(&t.matched),
+		BytesHashed: atomic.LoadInt64(&t.bytesHashed),
+		Elapsed:     time.Since(t.start),
+		Done:        done,
+	}
+	if progress.Processed > 0 && !done {
+		progress.ETA = progress.Elapsed / time.Duration(progress.Processed) * time.Duration(progress.Listed-progress.Processed)
+	}
+	return
+}
+
+// track wraps check so processed entries, matches and hashed bytes are counted
+func (t *progressTracker) track(check func(input file.FileInfoEx) bool) func(input file.FileInfoEx) bool {
+	return func(input file.FileInfoEx) (matched bool) {
+		if matched = check(&hashCountingFileInfo{FileInfoEx: input, counter: &t.bytesHashed}); matched {
+			atomic.AddInt64(&t.matched, 1)
+		}
+		atomic.AddInt64(&t.processed, 1)
+		return
+	}
+}
+
+func (t *progressTracker) finish() {
+	close(t.stop)
+	<-t.stopped
+}
+
+// hashCountingFileInfo adds bytes read for checksum to counter as they are read. Cached checksums add nothing.
+type hashCountingFileInfo struct {
+	file.FileInfoEx
+	counter *int64
+}
+
+func (h *hashCountingFileInfo) Checksum() (cs []byte, err error) {
+	cs, _, err = h.CountedChecksum(nil)
+	return
+}
+
+func (h *hashCountingFileInfo) CountedChecksum(counter func(n int64)) ([]byte, bool, error) {
+	return file.CountedChecksum(h.FileInfoEx, func(n int64) {
+		atomic.AddInt64(h.counter, n)
+		if counter != nil {
+			counter(n)
+		}
+	})
+}
+
+func (h *hashCountingFileInfo) MimeResult() (*mimechecker.Result, error) {
+	return file.MimeResult(h.FileInfoEx)
+}
+
+func (h *hashCountingFileInfo) Attr(name string, cb file.AttrCallback) (interface{}, error) {
+	return file.Attr(h.FileInfoEx, name, cb)
+}
+
+func (h *hashCountingFileInfo) Stat() (file.Stat, bool) {
+	return file.StatOf(h.FileInfoEx)
+}
+
+// NewProgressBar returns OnProgress callback rendering single line progress bar to w (usually os.Stderr). Line is
+// redrawn with carriage return and finished with new line when Glob is done.
+func NewProgressBar(w io.Writer) func(progress Progress) {
+	const width = 30
+	return func(progress Progress) {
+		filled := int(progress.Percent() * width / 100)
+		bar := strings.Repeat("=", filled)
+		if filled < width {
+			bar += ">" + strings.Repeat(" ", width-filled-1)
+		}
+		line := fmt.Sprintf("\r[%s] %3.0f%% %d/%d files, %d matched, %s hashed", bar, progress.Percent(),
+			progress.Processed, progress.Listed, progress.Matched, formatBytes(progress.BytesHashed))
+		if progress.Done {
+			line += fmt.Sprintf(", took %s\n", progress.Elapsed.Round(time.Millisecond))
+		} else if progress.ETA > 0 {
+			line += fmt.Sprintf(", ETA %s", progress.ETA.Round(time.Second))
+		}
+		fmt.Fprint(w, line)
+	}
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for value := n / unit; value >= unit; value /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,61 @@
+package finder
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinder_OnProgress(t *testing.T) {
+	var items []file.FileInfoEx
+	for i := 0; i < 20; i++ {
+		items = append(items, &mockFileInfoEx{name: fmt.Sprintf("%02d", i), size: 100, checksum: []byte{byte(i % 2)}})
+	}
+	slow := NewFilter("slow", CostName, 0, func(fiex file.FileInfoEx) (bool, error) {
+		time.Sleep(5 * time.Millisecond)
+		return true, nil
+	})
+	var reportsMu sync.Mutex
+	var reports []Progress
+	result, err := New().SetGlobFunc(newMockGlobFunc(items)).SetCheckerConcurrency(1).
+		Where(slow).
+		Checksum("01").
+		OnProgress(20*time.Millisecond, func(progress Progress) {
+			reportsMu.Lock()
+			reports = append(reports, progress)
+			reportsMu.Unlock()
+		}).
+		Glob("*")
+	assert.NoError(t, err)
+	assert.Len(t, result, 10)
+	if assert.True(t, len(reports) > 1) {
+		first, last := reports[0], reports[len(reports)-1]
+		assert.False(t, first.Done)
+		assert.True(t, first.Processed < 20)
+		assert.True(t, first.ETA > 0)
+		assert.Equal(t, Progress{Listed: 20, Processed: 20, Matched: 10, BytesHashed: 2000, Elapsed: last.Elapsed, Done: true}, last)
+	}
+	assert.Error(t, New().OnProgress(0, nil).lastErr)
+
+	var last Progress
+	_, err = New().SetGlobFunc(newMockGlobFunc(items)).Checksum("01").
+		OnProgress(time.Hour, func(progress Progress) { last = progress }).
+		Glob("*")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), last.BytesHashed)
+}
+
+func TestNewProgressBar(t *testing.T) {
+	output := &bytes.Buffer{}
+	bar := NewProgressBar(output)
+	bar(Progress{Listed: 4, Processed: 1, Matched: 1, BytesHashed: 1536, ETA: 3 * time.Second})
+	assert.Equal(t, "\r[=======>                      ]  25% 1/4 files, 1 matched, 1.5 KiB hashed, ETA 3s", output.String())
+	output.Reset()
+	bar(Progress{Listed: 4, Processed: 4, Matched: 2, BytesHashed: 3 << 30, Elapsed: 1500 * time.Millisecond, Done: true})
+	assert.Equal(t, "\r[==============================] 100% 4/4 files, 2 matched, 3.0 GiB hashed, took 1.5s\n", output.String())
+}
@@ -0,0 +1,238 @@
+package finder
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/pkg/errors"
+)
+
+// Snapshots are JSON lines files. First line is SnapshotHeader, every following one is SnapshotEntry. Entries are
+// sorted by path so two snapshots can be compared with single streaming pass.
+
+const snapshotVersion = 1
+
+// SnapshotHeader describes snapshot
+type SnapshotHeader struct {
+	Version   int       `json:"version"`
+	Pattern   string    `json:"pattern"`
+	Created   time.Time `json:"created"`
+	Checksums bool      `json:"checksums"`
+}
+
+// SnapshotEntry describes single file of Glob result. Checksum is hex encoded FileInfoEx.Checksum, empty if snapshot
+// has been written without checksums.
+type SnapshotEntry struct {
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mtime"`
+	Checksum string    `json:"checksum,omitempty"`
+}
+
+// WriteSnapshot globs files and writes snapshot of result to w. With withChecksums every file is hashed using checker
+// pool.
+func (f *Finder) WriteSnapshot(w io.Writer, pattern string, withChecksums bool) (err error) {
+	var globResult []file.FileInfoEx
+	if globResult, err = f.Glob(pattern); err != nil {
+		return
+	}
+	entries := make([]SnapshotEntry, len(globResult))
+	errs := make([]error, len(globResult))
+	runParallel(f.numCheckers, len(globResult), func(idx int) {
+		info := globResult[idx]
+		entries[idx] = SnapshotEntry{Path: fileInfoExPath(info), Size: info.Size(), ModTime: info.ModTime().UTC()}
+		if withChecksums {
+			var digest []byte
+			if digest, errs[idx] = info.Checksum(); errs[idx] == nil {
+				entries[idx].Checksum = fmt.Sprintf("%x", digest)
+			}
+		}
+	})
+	for idx, entryErr := range errs {
+		if entryErr != nil {
+			return errors.Wrap(entryErr, "checksum "+entries[idx].Path)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+	if err = encoder.Encode(SnapshotHeader{snapshotVersion, pattern, time.Now().UTC(), withChecksums}); err != nil {
+		return errors.Wrap(err, "header")
+	}
+	for _, entry := range entries {
+		if err = encoder.Encode(entry); err != nil {
+			return errors.Wrap(err, "entry")
+		}
+	}
+	return buffered.Flush()
+}
+
+// SnapshotReader reads snapshot entry by entry
+type SnapshotReader struct {
+	Header   SnapshotHeader
+	decoder  *json.Decoder
+	lastPath string
+}
+
+// NewSnapshotReader reads header of snapshot
+func NewSnapshotReader(r io.Reader) (reader *SnapshotReader, err error) {
+	reader = &SnapshotReader{decoder: json.NewDecoder(bufio.NewReader(r))}
+	if err = reader.decoder.Decode(&reader.Header); err != nil {
+		return nil, errors.Wrap(err, "snapshot header")
+	}
+	if reader.Header.Version != snapshotVersion {
+		return nil, errors.Errorf("unsupported snapshot version %d", reader.Header.Version)
+	}
+	return
+}
+
+// Next returns next entry. io.EOF is returned after last one. Entries out of path order are reported as error.
+func (r *SnapshotReader) Next() (entry SnapshotEntry, err error) {
+	if err = r.decoder.Decode(&entry); err != nil {
+		if err != io.EOF {
+			err = errors.Wrap(err, "snapshot entry")
+		}
+		return
+	}
+	if entry.Path <= r.lastPath && r.lastPath != "" {
+		err = errors.Errorf("snapshot entries are not sorted: %q after %q", entry.Path, r.lastPath)
+		return
+	}
+	r.lastPath = entry.Path
+	return
+}
+
+// SnapshotChangeType is kind of difference between snapshots
+type SnapshotChangeType string
+
+const (
+	SnapshotAdded   SnapshotChangeType = "added"
+	SnapshotRemoved SnapshotChangeType = "removed"
+	SnapshotGrown   SnapshotChangeType = "grown"
+	SnapshotShrunk  SnapshotChangeType = "shrunk"
+	// SnapshotContentChanged is reported for files of unchanged size which checksum differs. If either snapshot has no
+	// checksums modification time is compared instead.
+	SnapshotContentChanged SnapshotChangeType = "content-changed"
+	// SnapshotRenamed is reported for removed and added files with equal checksum
+	SnapshotRenamed SnapshotChangeType = "renamed"
+)
+
+// SnapshotChange describes single difference. Old is nil for added files, New for removed ones.
+type SnapshotChange struct {
+	Type SnapshotChangeType
+	Old  *SnapshotEntry
+	New  *SnapshotEntry
+}
+
+// DiffSnapshots compares old and new snapshot and calls report for every difference. Snapshots are merged in single
+// pass, only added and removed entries are kept in memory for rename detection. Changes of present files are reported
+// first in path order, then renames and at last remaining removed and added files. Error returned by report stops
+// comparison.
+func DiffSnapshots(oldSnapshot, newSnapshot io.Reader, report func(change SnapshotChange) error) (err error) {
+	var oldReader, newReader *SnapshotReader
+	if oldReader, err = NewSnapshotReader(oldSnapshot); err != nil {
+		return errors.Wrap(err, "old")
+	}
+	if newReader, err = NewSnapshotReader(newSnapshot); err != nil {
+		return errors.Wrap(err, "new")
+	}
+	var removed, added []*SnapshotEntry
+	oldEntry, oldErr := oldReader.Next()
+	newEntry, newErr := newReader.Next()
+	for oldErr == nil || newErr == nil {
+		if oldErr != nil && oldErr != io.EOF {
+			return errors.Wrap(oldErr, "old")
+		}
+		if newErr != nil && newErr != io.EOF {
+			return errors.Wrap(newErr, "new")
+		}
+		switch {
+		case newErr == io.EOF || (oldErr == nil && oldEntry.Path < newEntry.Path):
+			entry := oldEntry
+			removed = append(removed, &entry)
+			oldEntry, oldErr = oldReader.Next()
+		case oldErr == io.EOF || newEntry.Path < oldEntry.Path:
+			entry := newEntry
+			added = append(added, &entry)
+			newEntry, newErr = newReader.Next()
+		default:
+			if change, changed := compareSnapshotEntries(oldEntry, newEntry); changed {
+				if err = report(change); err != nil {
+					return
+				}
+			}
+			oldEntry, oldErr = oldReader.Next()
+			newEntry, newErr = newReader.Next()
+		}
+	}
+	if oldErr != io.EOF {
+		return errors.Wrap(oldErr, "old")
+	}
+	if newErr != io.EOF {
+		return errors.Wrap(newErr, "new")
+	}
+	return reportAddedAndRemoved(removed, added, report)
+}
+
+func compareSnapshotEntries(oldEntry, newEntry SnapshotEntry) (change SnapshotChange, changed bool) {
+	change = SnapshotChange{Old: &oldEntry, New: &newEntry}
+	switch {
+	case newEntry.Size > oldEntry.Size:
+		change.Type = SnapshotGrown
+	case newEntry.Size < oldEntry.Size:
+		change.Type = SnapshotShrunk
+	case oldEntry.Checksum != "" && newEntry.Checksum != "":
+		if oldEntry.Checksum == newEntry.Checksum {
+			return change, false
+		}
+		change.Type = SnapshotContentChanged
+	case !oldEntry.ModTime.Equal(newEntry.ModTime):
+		change.Type = SnapshotContentChanged
+	default:
+		return change, false
+	}
+	return change, true
+}
+
+func reportAddedAndRemoved(removed, added []*SnapshotEntry, report func(change SnapshotChange) error) (err error) {
+	removedByChecksum := map[string][]*SnapshotEntry{}
+	for _, entry := range removed {
+		if entry.Checksum != "" {
+			removedByChecksum[entry.Checksum] = append(removedByChecksum[entry.Checksum], entry)
+		}
+	}
+	renamed := map[*SnapshotEntry]bool{}
+	for _, entry := range added {
+		candidates := removedByChecksum[entry.Checksum]
+		if entry.Checksum == "" || len(candidates) == 0 {
+			continue
+		}
+		removedByChecksum[entry.Checksum] = candidates[1:]
+		renamed[candidates[0]], renamed[entry] = true, true
+		if err = report(SnapshotChange{Type: SnapshotRenamed, Old: candidates[0], New: entry}); err != nil {
+			return
+		}
+	}
+	for _, entry := range removed {
+		if !renamed[entry] {
+			if err = report(SnapshotChange{Type: SnapshotRemoved, Old: entry}); err != nil {
+				return
+			}
+		}
+	}
+	for _, entry := range added {
+		if !renamed[entry] {
+			if err = report(SnapshotChange{Type: SnapshotAdded, New: entry}); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
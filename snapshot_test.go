@@ -0,0 +1,105 @@
+package finder
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	dir := createTestTree(t, nil)
+	write := func(name, content string) {
+		writeTestFile(t, filepath.Join(dir, name), content)
+	}
+	write("grown", "1")
+	write("shrunk", "1234")
+	write("changed", "abcd")
+	write("same", "same")
+	write("renamed-from", "renamed content")
+	write("removed", "removed")
+	snapshot := func(withChecksums bool) *bytes.Buffer {
+		buf := &bytes.Buffer{}
+		if err := New().WriteSnapshot(buf, dir+"/*", withChecksums); err != nil {
+			t.Fatal(err)
+		}
+		return buf
+	}
+	before, beforeNoChecksums := snapshot(true), snapshot(false)
+	write("grown", "12")
+	write("shrunk", "1")
+	write("changed", "dcba")
+	if err := os.Chtimes(filepath.Join(dir, "changed"), time.Now().Add(time.Hour), time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, "renamed-from"), filepath.Join(dir, "renamed-to")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "removed")); err != nil {
+		t.Fatal(err)
+	}
+	write("added", "added")
+	after, afterNoChecksums := snapshot(true), snapshot(false)
+
+	diff := func(oldSnapshot, newSnapshot *bytes.Buffer) (changes []string) {
+		err := DiffSnapshots(oldSnapshot, newSnapshot, func(change SnapshotChange) error {
+			description := string(change.Type)
+			if change.Old != nil {
+				description += " " + filepath.Base(change.Old.Path)
+			}
+			if change.New != nil {
+				description += " " + filepath.Base(change.New.Path)
+			}
+			changes = append(changes, description)
+			return nil
+		})
+		assert.NoError(t, err)
+		return
+	}
+	t.Run("WithChecksums", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"content-changed changed changed",
+			"grown grown grown",
+			"shrunk shrunk shrunk",
+			"renamed renamed-from renamed-to",
+			"removed removed",
+			"added added",
+		}, diff(before, after))
+	})
+	t.Run("WithoutChecksums", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"content-changed changed changed",
+			"grown grown grown",
+			"shrunk shrunk shrunk",
+			"removed removed",
+			"removed renamed-from",
+			"added added",
+			"added renamed-to",
+		}, diff(beforeNoChecksums, afterNoChecksums))
+	})
+}
+
+func TestSnapshotReader(t *testing.T) {
+	header := `{"version":1,"pattern":"*","created":"2020-01-01T00:00:00Z","checksums":false}` + "\n"
+	t.Run("Unsorted", func(t *testing.T) {
+		reader, err := NewSnapshotReader(strings.NewReader(header +
+			`{"path":"/b","size":1,"mtime":"2020-01-01T00:00:00Z"}` + "\n" +
+			`{"path":"/a","size":1,"mtime":"2020-01-01T00:00:00Z"}` + "\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry, err := reader.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, SnapshotEntry{Path: "/b", Size: 1, ModTime: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}, entry)
+		_, err = reader.Next()
+		assert.Error(t, err)
+	})
+	t.Run("UnsupportedVersion", func(t *testing.T) {
+		_, err := NewSnapshotReader(strings.NewReader(`{"version":2}`))
+		assert.Error(t, err)
+	})
+}
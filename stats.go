@@ -0,0 +1,262 @@
+package finder
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/duffpl/go-finder/mimechecker"
+	"github.com/pkg/errors"
+)
+
+// sniffLen is number of bytes content based MIME detectors read
+const sniffLen = 512
+
+// Stats describes single Glob run. ChecksumBytes are counted as checksum callback reads them, SniffBytes are estimated
+// from sizes of files as MIME sniffing reads at most 512 bytes. Cache hits count values (checksum, MIME, attributes) reused instead of being computed
+// again.
+type Stats struct {
+	// Listed is number of files returned by glob function
+	Listed int64
+	// Evaluated is number of files checked against filters, Matched number of those that passed all of them
+	Evaluated int64
+	Matched   int64
+	// Filters are statistics of every filter in order they were checked at the end of run
+	Filters       []FilterStats
+	ChecksumBytes int64
+	SniffBytes    int64
+	CacheHits     int64
+	CacheMisses   int64
+	// Errors is number of filter errors by type (not-exist, permission or Go type of error)
+	Errors   map[string]int64
+	Duration time.Duration
+}
+
+// CollectStats enables collecting Stats during Glob. It adds timing of every filter check, so it's disabled by
+// default.
+func (f *Finder) CollectStats() *Finder {
+	f.stats = true
+	return f
+}
+
+// OnStats enables collecting Stats and calls hook with them after every Glob. See ExpvarStatsHook and
+// Stats.WritePrometheus for exporting.
+func (f *Finder) OnStats(hook func(stats *Stats)) *Finder {
+	f.stats = true
+	f.statsHook = hook
+	return f
+}
+
+// Stats returns statistics of last Glob or nil if collecting wasn't enabled
+func (f *Finder) Stats() *Stats {
+	f.planMu.Lock()
+	defer f.planMu.Unlock()
+	if f.plan == nil {
+		return nil
+	}
+	return f.plan.snapshot()
+}
+
+func (p *planner) snapshot() (stats *Stats) {
+	stats = &Stats{
+		Listed:        atomic.LoadInt64(&p.listed),
+		Evaluated:     atomic.LoadInt64(&p.checked),
+		Matched:       atomic.LoadInt64(&p.matched),
+		Filters:       p.stats(),
+		ChecksumBytes: atomic.LoadInt64(&p.checksumBytes),
+		SniffBytes:    atomic.LoadInt64(&p.sniffBytes),
+		CacheHits:     atomic.LoadInt64(&p.cacheHits),
+		CacheMisses:   atomic.LoadInt64(&p.cacheMisses),
+		Errors:        map[string]int64{},
+		Duration:      time.Duration(atomic.LoadInt64(&p.duration)),
+	}
+	p.errorsMu.Lock()
+	for errType, count := range p.errors {
+		stats.Errors[errType] = count
+	}
+	p.errorsMu.Unlock()
+	return
+}
+
+func errorType(err error) string {
+	cause := errors.Cause(err)
+	switch {
+	case os.IsNotExist(cause):
+		return "not-exist"
+	case os.IsPermission(cause):
+		return "permission"
+	}
+	return fmt.Sprintf("%T", cause)
+}
+
+// statsFileInfo counts computed and reused values of FileInfoEx checked by filters
+type statsFileInfo struct {
+	file.FileInfoEx
+	plan         *planner
+	checksumDone bool
+	mimeDone     bool
+}
+
+func (s *statsFileInfo) Checksum() (cs []byte, err error) {
+	cs, _, err = s.CountedChecksum(nil)
+	return
+}
+
+// CountedChecksum counts bytes actually read by checksum callback. Items which can't tell whether checksum was cached
+// count as hit after first call.
+func (s *statsFileInfo) CountedChecksum(counter func(n int64)) (cs []byte, cached bool, err error) {
+	cs, cached, err = file.CountedChecksum(s.FileInfoEx, func(n int64) {
+		atomic.AddInt64(&s.plan.checksumBytes, n)
+		if counter != nil {
+			counter(n)
+		}
+	})
+	if cached || s.checksumDone {
+		atomic.AddInt64(&s.plan.cacheHits, 1)
+	} else {
+		atomic.AddInt64(&s.plan.cacheMisses, 1)
+	}
+	s.checksumDone = true
+	return
+}
+
+func (s *statsFileInfo) Stat() (file.Stat, bool) {
+	return file.StatOf(s.FileInfoEx)
+}
+
+func (s *statsFileInfo) Mime() (m string, err error) {
+	s.countMime()
+	return s.FileInfoEx.Mime()
+}
+
+func (s *statsFileInfo) MimeResult() (r *mimechecker.Result, err error) {
+	s.countMime()
+	return file.MimeResult(s.FileInfoEx)
+}
+
+func (s *statsFileInfo) countMime() {
+	if s.mimeDone {
+		atomic.AddInt64(&s.plan.cacheHits, 1)
+		return
+	}
+	s.mimeDone = true
+	atomic.AddInt64(&s.plan.cacheMisses, 1)
+	sniffed := s.Size()
+	if sniffed > sniffLen {
+		sniffed = sniffLen
+	}
+	atomic.AddInt64(&s.plan.sniffBytes, sniffed)
+}
+
+func (s *statsFileInfo) Attr(name string, cb file.AttrCallback) (v interface{}, err error) {
+	if cb == nil {
+		return file.Attr(s.FileInfoEx, name, nil)
+	}
+	if v, _ = file.Attr(s.FileInfoEx, name, nil); v != nil {
+		atomic.AddInt64(&s.plan.cacheHits, 1)
+		return
+	}
+	atomic.AddInt64(&s.plan.cacheMisses, 1)
+	return file.Attr(s.FileInfoEx, name, cb)
+}
+
+// WritePrometheus writes stats in Prometheus text exposition format. Metric names start with namespace. Filters with
+// same name and cost are summed up.
+func (s *Stats) WritePrometheus(w io.Writer, namespace string) (err error) {
+	write := func(format string, args ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+	counter := func(name, help string, value interface{}) {
+		write("# HELP %s_%s %s\n# TYPE %s_%s counter\n%s_%s %v\n", namespace, name, help, namespace, name, namespace, name, value)
+	}
+	counter("files_listed_total", "Files returned by glob function.", s.Listed)
+	counter("files_evaluated_total", "Files checked against filters.", s.Evaluated)
+	counter("files_matched_total", "Files that passed all filters.", s.Matched)
+	counter("checksum_bytes_total", "Bytes read for checksums.", s.ChecksumBytes)
+	counter("sniff_bytes_total", "Bytes read for MIME sniffing.", s.SniffBytes)
+	counter("cache_hits_total", "Reused lazy values.", s.CacheHits)
+	counter("cache_misses_total", "Computed lazy values.", s.CacheMisses)
+	counter("duration_seconds_total", "Time spent checking filters.", s.Duration.Seconds())
+
+	type filterKey struct{ name, cost string }
+	var keys []filterKey
+	byKey := map[filterKey]FilterStats{}
+	for _, filter := range s.Filters {
+		key := filterKey{filter.Name, filter.Cost.String()}
+		sum, found := byKey[key]
+		if !found {
+			keys = append(keys, key)
+		}
+		sum.Checked += filter.Checked
+		sum.Passed += filter.Passed
+		sum.Errors += filter.Errors
+		sum.Total += filter.Total
+		byKey[key] = sum
+	}
+	filterCounter := func(name, help string, value func(stats FilterStats) interface{}) {
+		write("# HELP %s_%s %s\n# TYPE %s_%s counter\n", namespace, name, help, namespace, name)
+		for _, key := range keys {
+			write("%s_%s{filter=%q,cost=%q} %v\n", namespace, name, key.name, key.cost, value(byKey[key]))
+		}
+	}
+	filterCounter("filter_checked_total", "Files checked by filter.", func(stats FilterStats) interface{} { return stats.Checked })
+	filterCounter("filter_rejected_total", "Files rejected by filter.", func(stats FilterStats) interface{} { return stats.Rejected() })
+	filterCounter("filter_errors_total", "Filter checks failed with error.", func(stats FilterStats) interface{} { return stats.Errors })
+	filterCounter("filter_seconds_total", "Time spent in filter.", func(stats FilterStats) interface{} { return stats.Total.Seconds() })
+
+	var errTypes []string
+	for errType := range s.Errors {
+		errTypes = append(errTypes, errType)
+	}
+	sort.Strings(errTypes)
+	write("# HELP %s_errors_total Filter errors by type.\n# TYPE %s_errors_total counter\n", namespace, namespace)
+	for _, errType := range errTypes {
+		write("%s_errors_total{type=%q} %d\n", namespace, errType, s.Errors[errType])
+	}
+	return
+}
+
+// expvarMu serializes lookup and publishing of expvar maps, expvar.NewMap panics if name is taken in between
+var expvarMu sync.Mutex
+
+// ExpvarStatsHook returns OnStats hook which adds stats of every Glob to expvar map published under name. Map is
+// created if it doesn't exist yet, error is returned if name is already used by variable of other type.
+func ExpvarStatsHook(name string) (hook func(stats *Stats), err error) {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	var vars *expvar.Map
+	switch published := expvar.Get(name).(type) {
+	case nil:
+		vars = expvar.NewMap(name)
+	case *expvar.Map:
+		vars = published
+	default:
+		return nil, errors.Errorf("expvar %s is already published as %T", name, published)
+	}
+	return func(stats *Stats) {
+		vars.Add("files_listed", stats.Listed)
+		vars.Add("files_evaluated", stats.Evaluated)
+		vars.Add("files_matched", stats.Matched)
+		vars.Add("checksum_bytes", stats.ChecksumBytes)
+		vars.Add("sniff_bytes", stats.SniffBytes)
+		vars.Add("cache_hits", stats.CacheHits)
+		vars.Add("cache_misses", stats.CacheMisses)
+		vars.AddFloat("duration_seconds", stats.Duration.Seconds())
+		for _, filter := range stats.Filters {
+			vars.Add("filter_checked:"+filter.Name, filter.Checked)
+			vars.Add("filter_rejected:"+filter.Name, filter.Rejected())
+			vars.AddFloat("filter_seconds:"+filter.Name, filter.Total.Seconds())
+		}
+		for errType, count := range stats.Errors {
+			vars.Add("errors:"+errType, count)
+		}
+	}, nil
+}
@@ -0,0 +1,88 @@
+package finder
+
+import (
+	"bytes"
+	"expvar"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinder_CollectStats(t *testing.T) {
+	mockGlob := newMockGlobFunc([]file.FileInfoEx{
+		&mockFileInfoEx{name: "a.txt", size: 1000, mime: "text/plain", checksum: []byte{1}},
+		&mockFileInfoEx{name: "b.txt", size: 100, mime: "text/plain", checksum: []byte{2}},
+		&mockFileInfoEx{name: "c.log", size: 100, mime: "text/plain", checksum: []byte{1}},
+		&mockFileInfoEx{name: "d.txt", size: 100, mime: "image/png", checksum: []byte{1}},
+	})
+	missing := NewFilter("missing", CostMetadata, NeedStat, func(fiex file.FileInfoEx) (bool, error) {
+		if fiex.Name() == "b.txt" {
+			return false, errors.Wrap(&os.PathError{Op: "open", Path: "b.txt", Err: os.ErrNotExist}, "missing")
+		}
+		return true, nil
+	})
+	attrs := NewFilter("attrs", CostHead, NeedHead, func(fiex file.FileInfoEx) (bool, error) {
+		file.Attr(fiex, "test", func(path string) (interface{}, error) { return 1, nil })
+		file.Attr(fiex, "test", func(path string) (interface{}, error) { return 1, nil })
+		return true, nil
+	})
+	var hooked *Stats
+	sut := New().SetGlobFunc(mockGlob).
+		RegexpName(`\.txt$`).
+		Where(missing).
+		Mime("text/plain").
+		Where(attrs).
+		Checksum("01").
+		OnStats(func(stats *Stats) { hooked = stats })
+	assert.Nil(t, sut.Stats())
+	result, err := sut.Glob("*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.txt"}, getFileNamesFromResult(result))
+
+	stats := sut.Stats()
+	assert.Equal(t, hooked, stats)
+	assert.Equal(t, int64(4), stats.Listed)
+	assert.Equal(t, int64(4), stats.Evaluated)
+	assert.Equal(t, int64(1), stats.Matched)
+	assert.Equal(t, int64(1000), stats.ChecksumBytes)
+	assert.Equal(t, int64(512+100), stats.SniffBytes)
+	assert.Equal(t, int64(1), stats.CacheHits)
+	assert.Equal(t, int64(4), stats.CacheMisses)
+	assert.Equal(t, map[string]int64{"not-exist": 1}, stats.Errors)
+	var rejected []int64
+	for _, filter := range stats.Filters {
+		rejected = append(rejected, filter.Rejected())
+	}
+	assert.Equal(t, []int64{1, 1, 1, 0, 0}, rejected)
+	assert.Equal(t, int64(1), stats.Filters[1].Errors)
+
+	_, err = sut.Glob("*")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), sut.Stats().ChecksumBytes)
+	assert.Equal(t, int64(3), sut.Stats().CacheHits)
+
+	output := &bytes.Buffer{}
+	assert.NoError(t, stats.WritePrometheus(output, "finder"))
+	assert.Contains(t, output.String(), "# TYPE finder_files_listed_total counter\nfinder_files_listed_total 4\n")
+	assert.Contains(t, output.String(), `finder_filter_rejected_total{filter="RegexpName",cost="name"} 1`)
+	assert.Contains(t, output.String(), `finder_errors_total{type="not-exist"} 1`)
+
+	hook, err := ExpvarStatsHook("finder_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook(stats)
+	hook(stats)
+	vars := expvar.Get("finder_test").(*expvar.Map)
+	assert.Equal(t, "8", vars.Get("files_listed").String())
+	assert.True(t, strings.HasPrefix(vars.Get("filter_checked:missing").String(), "6"))
+	_, err = ExpvarStatsHook("finder_test")
+	assert.NoError(t, err)
+	expvar.NewInt("finder_test_int")
+	_, err = ExpvarStatsHook("finder_test_int")
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package finder
+
+import (
+	"github.com/duffpl/go-finder/file"
+	"github.com/duffpl/go-finder/textchecker"
+)
+
+const (
+	textInfoAttr   = "text-info"
+	textBinaryAttr = "text-binary"
+)
+
+// TextInfo returns encoding and line ending analysis of file. Whole file is read.
+func TextInfo(fiex file.FileInfoEx) (info *textchecker.Info, err error) {
+	var v interface{}
+	if v, err = file.Attr(fiex, textInfoAttr, func(path string) (interface{}, error) {
+		return textchecker.ByPath(path)
+	}); err != nil {
+		return
+	}
+	info = v.(*textchecker.Info)
+	return
+}
+
+// IsBinary tells whether file looks binary judging by its head. Full TextInfo is reused when it's already computed.
+func IsBinary(fiex file.FileInfoEx) (binary bool, err error) {
+	var v interface{}
+	if v, _ = file.Attr(fiex, textInfoAttr, nil); v != nil {
+		binary = v.(*textchecker.Info).Binary
+		return
+	}
+	if v, err = file.Attr(fiex, textBinaryAttr, func(path string) (interface{}, error) {
+		return textchecker.BinaryByPath(path)
+	}); err != nil {
+		return
+	}
+	binary = v.(bool)
+	return
+}
+
+func (f *Finder) addTextInfoFilter(name string, cb func(info *textchecker.Info) bool) {
+	f.addInfoFilter(name, CostContent, func(fiex file.FileInfoEx) (interface{}, error) {
+		return TextInfo(fiex)
+	}, func(info interface{}) bool {
+		return cb(info.(*textchecker.Info))
+	})
+}
+
+func (f *Finder) addBinaryFilter(name string, binary bool) {
+	f.addInfoFilter(name, CostHead, func(fiex file.FileInfoEx) (interface{}, error) {
+		return IsBinary(fiex)
+	}, func(info interface{}) bool {
+		return info.(bool) == binary
+	})
+}
+
+// TextEncoding adds matching of files in any of given encodings
+func (f *Finder) TextEncoding(encodings ...textchecker.Encoding) *Finder {
+	if f.lastErr != nil { return f }
+	f.addTextInfoFilter("TextEncoding", func(info *textchecker.Info) bool {
+		for _, encoding := range encodings {
+			if info.Encoding == encoding {
+				return true
+			}
+		}
+		return false
+	})
+	return f
+}
+
+// LineEnding adds matching of text files using given line terminators. E.g. LineEnding(textchecker.Mixed) finds files
+// with inconsistent line endings.
+func (f *Finder) LineEnding(lineEnding textchecker.LineEnding) *Finder {
+	if f.lastErr != nil { return f }
+	f.addTextInfoFilter("LineEnding", func(info *textchecker.Info) bool {
+		return !info.Binary && info.LineEnding == lineEnding
+	})
+	return f
+}
+
+// TrailingNewline adds matching of text files that end (present = true) or don't end (present = false) with newline.
+// Empty files are treated as not ending with newline.
+func (f *Finder) TrailingNewline(present bool) *Finder {
+	if f.lastErr != nil { return f }
+	f.addTextInfoFilter("TrailingNewline", func(info *textchecker.Info) bool {
+		return !info.Binary && info.TrailingNewline == present
+	})
+	return f
+}
+
+// Binary adds matching of files that don't look like text. Only head of file is checked, use
+// TextEncoding(textchecker.Binary) to classify by whole content.
+func (f *Finder) Binary() *Finder {
+	if f.lastErr != nil { return f }
+	f.addBinaryFilter("Binary", true)
+	return f
+}
+
+// Text adds matching of files that look like text in any supported encoding. Only head of file is checked.
+func (f *Finder) Text() *Finder {
+	if f.lastErr != nil { return f }
+	f.addBinaryFilter("Text", false)
+	return f
+}
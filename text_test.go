@@ -0,0 +1,18 @@
+package finder
+
+import (
+	"testing"
+
+	"github.com/duffpl/go-finder/textchecker"
+)
+
+func TestFinder_TextFilters(t *testing.T) {
+	runFilterExpectations(t, nil, "./test_files/mime/*", []filterExpectation{
+		{"TextEncoding", New().TextEncoding(textchecker.UTF8, textchecker.UTF8BOM), []string{"text-with-bom.txt"}},
+		{"LineEnding", New().RegexpName(`\.(txt|yml)$`).LineEnding(textchecker.LF), []string{"text.txt"}},
+		{"TrailingNewline", New().RegexpName(`\.(txt|yml)$`).TrailingNewline(false),
+			[]string{"text-with-bom.txt", "text.txt", "yaml.yml"}},
+		{"Binary", New().RegexpName("^image").Binary(), []string{"image-gif-fake-audio.mp3", "image.jpg", "image.png"}},
+		{"Text", New().Text(), []string{"text-with-bom.txt", "text.txt", "yaml.yml"}},
+	})
+}
@@ -0,0 +1,317 @@
+package textchecker
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Encoding is string const enum for detected text encodings
+type Encoding string
+
+const (
+	ASCII   Encoding = "ascii"
+	UTF8    Encoding = "utf-8"
+	UTF8BOM Encoding = "utf-8-bom"
+	UTF16LE Encoding = "utf-16le"
+	UTF16BE Encoding = "utf-16be"
+	// Latin1 is a guess for 8 bit text which isn't valid UTF-8
+	Latin1 Encoding = "latin-1"
+	// Binary is reported for files that don't look like text at all
+	Binary Encoding = "binary"
+)
+
+// LineEnding is string const enum for line terminators used in file
+type LineEnding string
+
+const (
+	NoLineEnding LineEnding = "none"
+	LF           LineEnding = "lf"
+	CRLF         LineEnding = "crlf"
+	CR           LineEnding = "cr"
+	Mixed        LineEnding = "mixed"
+)
+
+// Info is result of text analysis. LineEnding and TrailingNewline are meaningless for binary files.
+type Info struct {
+	Encoding        Encoding
+	LineEnding      LineEnding
+	TrailingNewline bool
+	Binary          bool
+}
+
+const (
+	sniffSize = 4096
+	// share of control characters above which file is treated as binary
+	maxControlRatio = 0.1
+)
+
+var (
+	bomUTF8    = []byte{0xef, 0xbb, 0xbf}
+	bomUTF16LE = []byte{0xff, 0xfe}
+	bomUTF16BE = []byte{0xfe, 0xff}
+)
+
+// ByPath analyzes file under path. Whole file is read.
+func ByPath(path string) (result *Info, err error) {
+	var handle *os.File
+	if handle, err = os.Open(path); err != nil {
+		err = errors.Wrap(err, "os.Open")
+		return
+	}
+	defer handle.Close()
+	return Analyze(handle)
+}
+
+// Analyze reads r until EOF and classifies its content
+func Analyze(r io.Reader) (result *Info, err error) {
+	reader := bufio.NewReaderSize(r, sniffSize)
+	head, _ := reader.Peek(sniffSize)
+	result = &Info{}
+	var bomSize, unitSize int
+	result.Encoding, bomSize, unitSize = sniffEncoding(head)
+	reader.Discard(bomSize)
+	stats := &textStats{}
+	if unitSize == 2 {
+		err = stats.scanUTF16(reader, result.Encoding == UTF16BE)
+	} else {
+		err = stats.scan(reader)
+	}
+	if err != nil {
+		err = errors.Wrap(err, "read")
+		return
+	}
+	if stats.binary() {
+		result.Encoding, result.Binary = Binary, true
+		return
+	}
+	if result.Encoding == "" {
+		switch {
+		case !stats.nonASCII:
+			result.Encoding = ASCII
+		case stats.validUTF8:
+			result.Encoding = UTF8
+		default:
+			result.Encoding = Latin1
+		}
+	}
+	result.LineEnding = stats.lineEnding()
+	result.TrailingNewline = stats.last == '\n' || stats.last == '\r'
+	return
+}
+
+// BinaryByPath tells whether file under path looks binary. Only head of file is read.
+func BinaryByPath(path string) (binary bool, err error) {
+	var handle *os.File
+	if handle, err = os.Open(path); err != nil {
+		err = errors.Wrap(err, "os.Open")
+		return
+	}
+	defer handle.Close()
+	return IsBinary(handle)
+}
+
+// IsBinary tells whether r looks binary judging by its first few kilobytes. Unlike Analyze it doesn't read until EOF,
+// so file with text head and binary tail is reported as text.
+func IsBinary(r io.Reader) (binary bool, err error) {
+	head := make([]byte, sniffSize)
+	var n int
+	if n, err = io.ReadFull(r, head); err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		err = errors.Wrap(err, "read")
+		return
+	}
+	err = nil
+	head = head[:n]
+	encoding, bomSize, unitSize := sniffEncoding(head)
+	reader := bufio.NewReader(bytes.NewReader(head[bomSize:]))
+	stats := &textStats{stopAtNul: true}
+	if unitSize == 2 {
+		stats.scanUTF16(reader, encoding == UTF16BE)
+	} else {
+		stats.scan(reader)
+	}
+	binary = stats.binary()
+	return
+}
+
+// sniffEncoding recognizes byte order mark or BOM-less UTF-16 in head. Returned encoding is empty for 8 bit encodings,
+// which are told apart only after whole content is scanned.
+func sniffEncoding(head []byte) (encoding Encoding, bomSize, unitSize int) {
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		return UTF8BOM, len(bomUTF8), 1
+	case bytes.HasPrefix(head, bomUTF16LE):
+		return UTF16LE, len(bomUTF16LE), 2
+	case bytes.HasPrefix(head, bomUTF16BE):
+		return UTF16BE, len(bomUTF16BE), 2
+	}
+	if encoding = guessUTF16(head); encoding != "" {
+		return encoding, 0, 2
+	}
+	return "", 0, 1
+}
+
+// guessUTF16 recognizes UTF-16 without byte order mark by zero bytes in every other position, which is typical for
+// text in Latin scripts
+func guessUTF16(head []byte) Encoding {
+	if len(head) < 4 {
+		return ""
+	}
+	evenZeros, oddZeros := 0, 0
+	for i := 0; i+1 < len(head); i += 2 {
+		if head[i] == 0 {
+			evenZeros++
+		}
+		if head[i+1] == 0 {
+			oddZeros++
+		}
+	}
+	units := len(head) / 2
+	switch {
+	case oddZeros*10 >= units*9 && evenZeros == 0:
+		return UTF16LE
+	case evenZeros*10 >= units*9 && oddZeros == 0:
+		return UTF16BE
+	}
+	return ""
+}
+
+type textStats struct {
+	total, control, nul int64
+	lf, crlf, cr        int64
+	nonASCII            bool
+	validUTF8           bool
+	last                rune
+	pendingCR           bool
+	// stopAtNul makes scan return at first NUL, which is enough to tell file is binary
+	stopAtNul bool
+}
+
+func (s *textStats) binary() bool {
+	return s.total > 0 && (s.nul > 0 || float64(s.control)/float64(s.total) > maxControlRatio)
+}
+
+func (s *textStats) char(c rune) {
+	s.total++
+	switch {
+	case c == 0:
+		s.nul++
+	case c == '\n':
+		if s.pendingCR {
+			s.crlf++
+		} else {
+			s.lf++
+		}
+	case c < 0x20 && c != '\t' && c != '\r' && c != '\f' && c != '\v' && c != 0x1b:
+		s.control++
+	}
+	if s.pendingCR && c != '\n' {
+		s.cr++
+	}
+	s.pendingCR = c == '\r'
+	s.last = c
+}
+
+func (s *textStats) finish() {
+	if s.pendingCR {
+		s.cr++
+	}
+}
+
+func (s *textStats) scan(r *bufio.Reader) (err error) {
+	s.validUTF8 = true
+	buf := make([]byte, 32*1024)
+	var carry []byte
+	for {
+		var n int
+		n, err = r.Read(buf)
+		chunk := append(carry, buf[:n]...)
+		carry = nil
+		if err == nil {
+			// incomplete rune at the end of chunk is validated together with next one
+			if cut := incompleteRuneStart(chunk); cut >= 0 {
+				carry = append([]byte(nil), chunk[cut:]...)
+				chunk = chunk[:cut]
+			}
+		}
+		for _, c := range chunk {
+			if c >= 0x80 {
+				s.nonASCII = true
+			}
+			s.char(rune(c))
+			if c == 0 && s.stopAtNul {
+				return nil
+			}
+		}
+		if s.validUTF8 && s.nonASCII && !utf8.Valid(chunk) {
+			s.validUTF8 = false
+		}
+		if err == io.EOF {
+			s.finish()
+			return nil
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// incompleteRuneStart returns index of last rune start if that rune is cut off, -1 otherwise
+func incompleteRuneStart(chunk []byte) int {
+	for i := len(chunk) - 1; i >= 0 && i >= len(chunk)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(chunk[i]) {
+			if !utf8.FullRune(chunk[i:]) {
+				return i
+			}
+			return -1
+		}
+	}
+	return -1
+}
+
+func (s *textStats) scanUTF16(r *bufio.Reader, bigEndian bool) error {
+	s.validUTF8 = false
+	unit := make([]byte, 2)
+	for {
+		if _, err := io.ReadFull(r, unit); err != nil {
+			s.finish()
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return err
+		}
+		c := rune(unit[0]) | rune(unit[1])<<8
+		if bigEndian {
+			c = rune(unit[1]) | rune(unit[0])<<8
+		}
+		if c >= 0x80 {
+			s.nonASCII = true
+		}
+		s.char(c)
+		if c == 0 && s.stopAtNul {
+			return nil
+		}
+	}
+}
+
+func (s *textStats) lineEnding() LineEnding {
+	kinds := 0
+	result := NoLineEnding
+	for _, kind := range []struct {
+		count  int64
+		ending LineEnding
+	}{{s.lf, LF}, {s.crlf, CRLF}, {s.cr, CR}} {
+		if kind.count > 0 {
+			kinds++
+			result = kind.ending
+		}
+	}
+	if kinds > 1 {
+		return Mixed
+	}
+	return result
+}
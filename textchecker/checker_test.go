@@ -0,0 +1,72 @@
+package textchecker
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing/iotest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	testExpectations := []struct {
+		name     string
+		content  []byte
+		expected Info
+	}{
+		{"Empty", []byte{}, Info{Encoding: ASCII, LineEnding: NoLineEnding}},
+		{"ASCII-LF", []byte("a\nb\n"), Info{Encoding: ASCII, LineEnding: LF, TrailingNewline: true}},
+		{"UTF8-CRLF", []byte("zażółć\r\ngęślą"), Info{Encoding: UTF8, LineEnding: CRLF}},
+		{"UTF8BOM", []byte("\xef\xbb\xbfabc\n"), Info{Encoding: UTF8BOM, LineEnding: LF, TrailingNewline: true}},
+		{"Latin1", []byte("caf\xe9\r"), Info{Encoding: Latin1, LineEnding: CR, TrailingNewline: true}},
+		{"Mixed", []byte("a\nb\r\nc"), Info{Encoding: ASCII, LineEnding: Mixed}},
+		{"UTF16LE-BOM", []byte("\xff\xfea\x00\r\x00\n\x00"), Info{Encoding: UTF16LE, LineEnding: CRLF, TrailingNewline: true}},
+		{"UTF16BE-NoBOM", []byte("\x00a\x00b\x00c\x00\n"), Info{Encoding: UTF16BE, LineEnding: LF, TrailingNewline: true}},
+		{"Binary", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), Info{Encoding: Binary, Binary: true}},
+	}
+	for _, expectation := range testExpectations {
+		t.Run(expectation.name, func(t *testing.T) {
+			info, err := Analyze(bytes.NewReader(expectation.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expectation.expected, *info)
+			binary, err := IsBinary(bytes.NewReader(expectation.content))
+			assert.NoError(t, err)
+			assert.Equal(t, expectation.expected.Binary, binary)
+		})
+	}
+	t.Run("IsBinaryReadsHeadOnly", func(t *testing.T) {
+		content := io.MultiReader(strings.NewReader(strings.Repeat("a", sniffSize)), iotest.ErrReader(errors.New("tail")))
+		binary, err := IsBinary(content)
+		assert.NoError(t, err)
+		assert.False(t, binary)
+	})
+	t.Run("RuneSplitBetweenChunks", func(t *testing.T) {
+		content := strings.Repeat("a", 32*1024-1) + "ż"
+		info, err := Analyze(strings.NewReader(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, UTF8, info.Encoding)
+	})
+}
+
+func TestByPath(t *testing.T) {
+	testExpectations := map[string]Encoding{
+		"text.txt":          ASCII,
+		"text-with-bom.txt": UTF8BOM,
+		"yaml.yml":          ASCII,
+		"image.png":         Binary,
+	}
+	for name, expected := range testExpectations {
+		info, err := ByPath("../test_files/mime/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, expected, info.Encoding, "file: %s", name)
+	}
+}
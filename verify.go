@@ -0,0 +1,170 @@
+package finder
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/duffpl/go-finder/checksum"
+	"github.com/duffpl/go-finder/file"
+	"github.com/pkg/errors"
+)
+
+// VerifyStatus is string const enum describing outcome of manifest verification for single file
+type VerifyStatus string
+
+const (
+	VerifyOK       VerifyStatus = "OK"
+	VerifyMismatch VerifyStatus = "MISMATCH"
+	VerifyMissing  VerifyStatus = "MISSING"
+	VerifyExtra    VerifyStatus = "EXTRA"
+)
+
+// VerifyResult describes single file checked against manifest. Path is relative to manifest base if possible.
+type VerifyResult struct {
+	Path      string             `json:"path"`
+	Status    VerifyStatus       `json:"status"`
+	Algorithm checksum.Algorithm `json:"algorithm,omitempty"`
+	Expected  string             `json:"expected,omitempty"`
+	Actual    string             `json:"actual,omitempty"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// VerifySummary holds counters of VerifyReport results. Errors counts files that couldn't be hashed, those are also
+// reported as mismatches.
+type VerifySummary struct {
+	Total    int `json:"total"`
+	OK       int `json:"ok"`
+	Mismatch int `json:"mismatch"`
+	Missing  int `json:"missing"`
+	Extra    int `json:"extra"`
+	Errors   int `json:"errors"`
+}
+
+// VerifyReport is result of Finder.Verify. Results are sorted by path.
+type VerifyReport struct {
+	Results []VerifyResult `json:"results"`
+	Summary VerifySummary  `json:"summary"`
+}
+
+// Passed returns true if every manifest entry has been found and matched and there are no extra files
+func (r *VerifyReport) Passed() bool {
+	return r.Summary.OK == r.Summary.Total
+}
+
+// Verify checks files matched by pattern (and filters) against manifest. Manifest entries that are not in result set
+// are reported as MISSING, matched files without manifest entry as EXTRA. Digests are computed concurrently using
+// checker pool. MD5 digests are taken from FileInfoEx.Checksum so they are shared with Checksum filter.
+func (f *Finder) Verify(manifest *checksum.Manifest, pattern string) (report *VerifyReport, err error) {
+	var globResult []file.FileInfoEx
+	if globResult, err = f.Glob(pattern); err != nil {
+		return
+	}
+	var base string
+	if base, err = filepath.Abs(manifest.Base); err != nil {
+		err = errors.Wrap(err, "filepath.Abs")
+		return
+	}
+	byAbs := make(map[string]file.FileInfoEx, len(globResult))
+	for _, info := range globResult {
+		var abs string
+		if abs, err = info.Abs(); err != nil {
+			err = errors.Wrap(err, "abs")
+			return
+		}
+		byAbs[abs] = info
+	}
+	report = &VerifyReport{Results: make([]VerifyResult, len(manifest.Entries))}
+	var toCheck []int
+	for idx, entry := range manifest.Entries {
+		if _, found := byAbs[manifestEntryAbs(base, entry)]; found {
+			toCheck = append(toCheck, idx)
+		}
+	}
+	runParallel(f.numCheckers, len(toCheck), func(i int) {
+		entry := manifest.Entries[toCheck[i]]
+		report.Results[toCheck[i]] = verifyEntry(entry, byAbs[manifestEntryAbs(base, entry)])
+	})
+	for idx, entry := range manifest.Entries {
+		if _, found := byAbs[manifestEntryAbs(base, entry)]; !found {
+			report.Results[idx] = VerifyResult{
+				Path:      entry.Path,
+				Status:    VerifyMissing,
+				Algorithm: entry.Algorithm,
+				Expected:  fmt.Sprintf("%x", entry.Digest),
+			}
+		}
+	}
+	for _, entry := range manifest.Entries {
+		delete(byAbs, manifestEntryAbs(base, entry))
+	}
+	for abs := range byAbs {
+		path := abs
+		if rel, relErr := filepath.Rel(base, abs); relErr == nil {
+			path = rel
+		}
+		report.Results = append(report.Results, VerifyResult{Path: path, Status: VerifyExtra})
+	}
+	sort.Slice(report.Results, func(i, j int) bool {
+		return report.Results[i].Path < report.Results[j].Path
+	})
+	for _, result := range report.Results {
+		report.Summary.add(result)
+	}
+	return
+}
+
+func (s *VerifySummary) add(result VerifyResult) {
+	s.Total++
+	switch result.Status {
+	case VerifyOK:
+		s.OK++
+	case VerifyMismatch:
+		s.Mismatch++
+	case VerifyMissing:
+		s.Missing++
+	case VerifyExtra:
+		s.Extra++
+	}
+	if result.Error != "" {
+		s.Errors++
+	}
+}
+
+func manifestEntryAbs(base string, entry checksum.ManifestEntry) string {
+	if filepath.IsAbs(entry.Path) {
+		return filepath.Clean(entry.Path)
+	}
+	return filepath.Join(base, entry.Path)
+}
+
+func verifyEntry(entry checksum.ManifestEntry, info file.FileInfoEx) (result VerifyResult) {
+	result = VerifyResult{
+		Path:      entry.Path,
+		Status:    VerifyMismatch,
+		Algorithm: entry.Algorithm,
+		Expected:  fmt.Sprintf("%x", entry.Digest),
+	}
+	var (
+		actual []byte
+		err    error
+	)
+	if entry.Algorithm == checksum.MD5 {
+		actual, err = info.Checksum()
+	} else {
+		var abs string
+		if abs, err = info.Abs(); err == nil {
+			actual, err = entry.Algorithm.ByPath(abs)
+		}
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	result.Actual = fmt.Sprintf("%x", actual)
+	if bytes.Equal(actual, entry.Digest) {
+		result.Status = VerifyOK
+	}
+	return
+}
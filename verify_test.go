@@ -0,0 +1,46 @@
+package finder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/duffpl/go-finder/checksum"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinder_Verify(t *testing.T) {
+	manifest, err := checksum.ParseManifest(strings.NewReader(strings.Join([]string{
+		"3b5d5c3712955042212316173ccf37be  3b5d5c3712955042212316173ccf37be",
+		"0000000000000000000000000000000000000000000000000000000000000000  60b725f10c9c85c70d97880dfe8191b3",
+		"3b5d5c3712955042212316173ccf37be  not-there",
+	}, "\n")), "test_files/checksum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := New().Verify(manifest, "./test_files/checksum/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := map[string]VerifyStatus{}
+	for _, result := range report.Results {
+		statuses[result.Path] = result.Status
+	}
+	assert.Equal(t, map[string]VerifyStatus{
+		"3b5d5c3712955042212316173ccf37be": VerifyOK,
+		"60b725f10c9c85c70d97880dfe8191b3": VerifyMismatch,
+		"not-there":                        VerifyMissing,
+	}, statuses)
+	assert.Equal(t, VerifySummary{Total: 3, OK: 1, Mismatch: 1, Missing: 1}, report.Summary)
+	assert.False(t, report.Passed())
+
+	t.Run("Extra", func(t *testing.T) {
+		manifest.Entries = manifest.Entries[:1]
+		report, err := New().Verify(manifest, "./test_files/checksum/*")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, VerifySummary{Total: 2, OK: 1, Extra: 1}, report.Summary)
+		assert.Equal(t, "60b725f10c9c85c70d97880dfe8191b3", report.Results[1].Path)
+		assert.Equal(t, VerifyExtra, report.Results[1].Status)
+	})
+}
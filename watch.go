@@ -0,0 +1,268 @@
+package finder
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/duffpl/go-finder/file"
+	"github.com/pkg/errors"
+)
+
+// WatchEventType is kind of change reported by Watcher
+type WatchEventType string
+
+const (
+	// WatchAdded is reported when file starts matching query (it's new or changed so that filters match it)
+	WatchAdded WatchEventType = "added"
+	// WatchRemoved is reported when matching file is deleted or changed so that filters don't match it anymore
+	WatchRemoved WatchEventType = "removed"
+	// WatchModified is reported when matching file changes and still matches
+	WatchModified WatchEventType = "modified"
+)
+
+// WatchEvent describes change of single file. Info is nil for removed files that no longer exist.
+type WatchEvent struct {
+	Type WatchEventType
+	Path string
+	Info file.FileInfoEx
+}
+
+// WatchOptions configures Watcher. Zero values are replaced by defaults.
+type WatchOptions struct {
+	// PollInterval is interval of full rescans. With inotify it's only safety net for missed notifications.
+	PollInterval time.Duration
+	// Debounce is time of quiet after notification before rescan is done, so bursts of changes cause single rescan
+	Debounce time.Duration
+	// MaxDebounce limits how long rescan can be postponed by notifications that keep coming, e.g. for file that is
+	// continuously written
+	MaxDebounce time.Duration
+	// DisableInotify forces polling
+	DisableInotify bool
+}
+
+const (
+	DefaultWatchPollInterval = 5 * time.Second
+	DefaultWatchDebounce     = 200 * time.Millisecond
+	DefaultWatchMaxDebounce  = 2 * time.Second
+)
+
+// watchTrigger notifies about changes in watched tree
+type watchTrigger interface {
+	C() <-chan struct{}
+	// refresh adds watches for directories created since last call
+	refresh()
+	Close() error
+}
+
+type watchState struct {
+	modTime time.Time
+	size    int64
+	mode    os.FileMode
+	matched bool
+	info    file.FileInfoEx
+}
+
+// Watcher continuously reports files that start or stop matching Finder query
+type Watcher struct {
+	finder  *Finder
+	pattern string
+	opts    WatchOptions
+	state   map[string]watchState
+	trigger watchTrigger
+
+	events    chan WatchEvent
+	errors    chan error
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// Watch scans files matching glob pattern and starts reporting changes of query result. Changed files are detected by
+// modification time, size and mode, only those are checked against filters again. Files matching at start aren't
+// reported. On Linux directories under glob base are watched with inotify, elsewhere (or when inotify can't be used)
+// tree is polled.
+func (f *Finder) Watch(pattern string, opts WatchOptions) (watcher *Watcher, err error) {
+	if f.lastErr != nil {
+		err = f.lastErr
+		return
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultWatchPollInterval
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultWatchDebounce
+	}
+	if opts.MaxDebounce <= 0 {
+		opts.MaxDebounce = DefaultWatchMaxDebounce
+	}
+	watcher = &Watcher{
+		finder:  f,
+		pattern: pattern,
+		opts:    opts,
+		state:   map[string]watchState{},
+		events:  make(chan WatchEvent, 64),
+		errors:  make(chan error, 16),
+		done:    make(chan struct{}),
+	}
+	if _, err = watcher.rescan(); err != nil {
+		return nil, err
+	}
+	if !opts.DisableInotify {
+		// inotify is optional, polling keeps working without it
+		watcher.trigger, _ = newInotifyTrigger(globBase(pattern))
+	}
+	watcher.wg.Add(1)
+	go watcher.loop()
+	return
+}
+
+// Events returns channel of changes. It's closed by Close.
+func (w *Watcher) Events() <-chan WatchEvent {
+	return w.events
+}
+
+// Errors returns channel of rescan errors. Errors are dropped if nobody reads them.
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Close stops watching and closes Events channel
+func (w *Watcher) Close() (err error) {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		w.wg.Wait()
+		if w.trigger != nil {
+			err = w.trigger.Close()
+		}
+		close(w.events)
+	})
+	return
+}
+
+func (w *Watcher) loop() {
+	defer w.wg.Done()
+	ticker := time.NewTicker(w.opts.PollInterval)
+	defer ticker.Stop()
+	var notifications <-chan struct{}
+	if w.trigger != nil {
+		notifications = w.trigger.C()
+	}
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		case <-notifications:
+			if !w.debounce(notifications) {
+				return
+			}
+		}
+		events, err := w.rescan()
+		if err != nil {
+			select {
+			case w.errors <- err:
+			default:
+			}
+			continue
+		}
+		if w.trigger != nil {
+			w.trigger.refresh()
+		}
+		for _, event := range events {
+			select {
+			case w.events <- event:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+// debounce waits until there are no notifications for Debounce period, but no longer than MaxDebounce. Returns false
+// if watcher has been closed.
+func (w *Watcher) debounce(notifications <-chan struct{}) bool {
+	timer := time.NewTimer(w.opts.Debounce)
+	defer timer.Stop()
+	deadline := time.NewTimer(w.opts.MaxDebounce)
+	defer deadline.Stop()
+	for {
+		select {
+		case <-w.done:
+			return false
+		case <-deadline.C:
+			return true
+		case <-notifications:
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(w.opts.Debounce)
+		case <-timer.C:
+			return true
+		}
+	}
+}
+
+// rescan globs files and checks filters for new and changed ones
+func (w *Watcher) rescan() (events []WatchEvent, err error) {
+	var globResult []file.FileInfoEx
+	if globResult, err = w.finder.glob(w.pattern); err != nil {
+		err = errors.Wrap(err, "glob")
+		return
+	}
+	current := make(map[string]watchState, len(globResult))
+	var changed []string
+	for _, info := range globResult {
+		path := fileInfoExPath(info)
+		state := watchState{modTime: info.ModTime(), size: info.Size(), mode: info.Mode(), info: info}
+		if previous, found := w.state[path]; found && previous.modTime.Equal(state.modTime) &&
+			previous.size == state.size && previous.mode == state.mode {
+			state.matched = previous.matched
+			state.info = previous.info
+		} else {
+			changed = append(changed, path)
+		}
+		current[path] = state
+	}
+	matched := make([]bool, len(changed))
+	runParallel(w.finder.numCheckers, len(changed), func(idx int) {
+		matched[idx] = w.finder.checkFilters(current[changed[idx]].info)
+	})
+	for idx, path := range changed {
+		state := current[path]
+		state.matched = matched[idx]
+		current[path] = state
+		previous, existed := w.state[path]
+		switch {
+		case state.matched && existed && previous.matched:
+			events = append(events, WatchEvent{WatchModified, path, state.info})
+		case state.matched:
+			events = append(events, WatchEvent{WatchAdded, path, state.info})
+		case existed && previous.matched:
+			events = append(events, WatchEvent{WatchRemoved, path, state.info})
+		}
+	}
+	for path, previous := range w.state {
+		if _, found := current[path]; !found && previous.matched {
+			events = append(events, WatchEvent{Type: WatchRemoved, Path: path})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Path < events[j].Path
+	})
+	w.state = current
+	return
+}
+
+// watchedDirs returns base and all directories below it
+func watchedDirs(base string) (dirs []string) {
+	filepath.Walk(base, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	return
+}
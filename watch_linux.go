@@ -0,0 +1,106 @@
+//go:build linux
+// +build linux
+
+package finder
+
+import (
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+const inotifyMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_ATTRIB | syscall.IN_DELETE_SELF
+
+type inotifyTrigger struct {
+	base   string
+	fd     int
+	handle *os.File
+	c      chan struct{}
+
+	mu      sync.Mutex
+	watched map[string]struct{}
+}
+
+func newInotifyTrigger(base string) (trigger watchTrigger, err error) {
+	var fd int
+	if fd, err = syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK); err != nil {
+		err = errors.Wrap(err, "inotify_init1")
+		return
+	}
+	t := &inotifyTrigger{
+		base: base,
+		fd:   fd,
+		// non blocking descriptor is handled by runtime poller, so Close interrupts pending Read
+		handle:  os.NewFile(uintptr(fd), "inotify"),
+		c:       make(chan struct{}, 1),
+		watched: map[string]struct{}{},
+	}
+	t.refresh()
+	go t.read()
+	return t, nil
+}
+
+func (t *inotifyTrigger) C() <-chan struct{} {
+	return t.c
+}
+
+func (t *inotifyTrigger) refresh() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, dir := range watchedDirs(t.base) {
+		if _, found := t.watched[dir]; found {
+			continue
+		}
+		// directories that can't be watched are still covered by polling
+		if _, err := syscall.InotifyAddWatch(t.fd, dir, inotifyMask); err == nil {
+			t.watched[dir] = struct{}{}
+		}
+	}
+}
+
+func (t *inotifyTrigger) read() {
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		n, err := t.handle.Read(buf)
+		if err != nil {
+			return
+		}
+		if n <= 0 {
+			continue
+		}
+		if t.hasNewDirectory(buf[:n]) {
+			t.refresh()
+		}
+		select {
+		case t.c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// hasNewDirectory checks if events report created or moved in directory, which needs its own watch. Watches of
+// removed directories are dropped by kernel, so they're forgotten here as well.
+func (t *inotifyTrigger) hasNewDirectory(events []byte) (found bool) {
+	for offset := 0; offset+syscall.SizeofInotifyEvent <= len(events); {
+		event := (*syscall.InotifyEvent)(unsafe.Pointer(&events[offset]))
+		if event.Mask&syscall.IN_ISDIR != 0 && event.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+			found = true
+		}
+		if event.Mask&syscall.IN_IGNORED != 0 {
+			t.mu.Lock()
+			t.watched = map[string]struct{}{}
+			t.mu.Unlock()
+			found = true
+		}
+		offset += syscall.SizeofInotifyEvent + int(event.Len)
+	}
+	return
+}
+
+func (t *inotifyTrigger) Close() error {
+	return t.handle.Close()
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package finder
+
+import "errors"
+
+func newInotifyTrigger(base string) (watchTrigger, error) {
+	return nil, errors.New("inotify is available only on linux")
+}
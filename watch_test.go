@@ -0,0 +1,99 @@
+package finder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func waitWatchEvent(t *testing.T, watcher *Watcher) (event WatchEvent) {
+	select {
+	case event = <-watcher.Events():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for watch event")
+	}
+	return
+}
+
+func TestFinder_Watch(t *testing.T) {
+	for name, opts := range map[string]WatchOptions{
+		"Inotify": {PollInterval: time.Hour, Debounce: 20 * time.Millisecond},
+		"Polling": {PollInterval: 30 * time.Millisecond, DisableInotify: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if name == "Inotify" {
+				if trigger, err := newInotifyTrigger(os.TempDir()); err != nil {
+					t.Skip(err)
+				} else {
+					trigger.Close()
+				}
+			}
+			dir := createTestTree(t, nil)
+			write := func(name, content string) {
+				writeTestFile(t, filepath.Join(dir, name), content)
+			}
+			write("existing.txt", "existing")
+			watcher, err := New().RegexpName(`\.txt$`).Watch(dir+"/**", opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer watcher.Close()
+
+			write("ignored.log", "log")
+			write("new.txt", "new")
+			assert.Equal(t, WatchEvent{WatchAdded, filepath.Join(dir, "new.txt"), nil}, withoutInfo(waitWatchEvent(t, watcher)))
+
+			write("new.txt", "new content")
+			assert.Equal(t, WatchEvent{WatchModified, filepath.Join(dir, "new.txt"), nil}, withoutInfo(waitWatchEvent(t, watcher)))
+
+			if err = os.Rename(filepath.Join(dir, "existing.txt"), filepath.Join(dir, "existing.log")); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, WatchEvent{WatchRemoved, filepath.Join(dir, "existing.txt"), nil}, waitWatchEvent(t, watcher))
+
+			if err = os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(50 * time.Millisecond)
+			write("sub/nested.txt", "nested")
+			event := waitWatchEvent(t, watcher)
+			assert.Equal(t, WatchEvent{WatchAdded, filepath.Join(dir, "sub", "nested.txt"), nil}, withoutInfo(event))
+			assert.Equal(t, "nested.txt", event.Info.Name())
+
+			assert.NoError(t, watcher.Close())
+			_, open := <-watcher.Events()
+			assert.False(t, open)
+		})
+	}
+}
+
+func withoutInfo(event WatchEvent) WatchEvent {
+	event.Info = nil
+	return event
+}
+
+func TestWatcher_debounceMaxDelay(t *testing.T) {
+	watcher := &Watcher{
+		opts: WatchOptions{Debounce: 50 * time.Millisecond, MaxDebounce: 200 * time.Millisecond},
+		done: make(chan struct{}),
+	}
+	notifications := make(chan struct{})
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case notifications <- struct{}{}:
+				time.Sleep(10 * time.Millisecond)
+			case <-stop:
+				return
+			}
+		}
+	}()
+	start := time.Now()
+	assert.True(t, watcher.debounce(notifications))
+	assert.True(t, time.Since(start) < time.Second)
+}
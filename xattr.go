@@ -0,0 +1,77 @@
+package finder
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/duffpl/go-finder/file"
+)
+
+const xattrsAttr = "xattrs"
+
+// Xattrs returns extended attributes of file keyed by full name, e.g. "user.origin". Only Linux is supported, files on
+// filesystems without xattrs have none.
+func Xattrs(fiex file.FileInfoEx) (xattrs map[string][]byte, err error) {
+	var v interface{}
+	if v, err = file.Attr(fiex, xattrsAttr, func(path string) (interface{}, error) {
+		return file.Xattrs(path)
+	}); err != nil {
+		return
+	}
+	xattrs = v.(map[string][]byte)
+	return
+}
+
+func (f *Finder) addXattrFilter(name string, cb func(xattrs map[string][]byte) bool) {
+	f.addInfoFilter(name, CostMetadata, func(fiex file.FileInfoEx) (interface{}, error) {
+		return Xattrs(fiex)
+	}, func(xattrs interface{}) bool {
+		return cb(xattrs.(map[string][]byte))
+	})
+}
+
+// HasXattr adds matching of files with extended attribute of given name, e.g. "user.origin"
+func (f *Finder) HasXattr(name string) *Finder {
+	if f.lastErr != nil { return f }
+	f.addXattrFilter("HasXattr", func(xattrs map[string][]byte) bool {
+		_, found := xattrs[name]
+		return found
+	})
+	return f
+}
+
+// XattrEquals adds matching of files which extended attribute has given value
+func (f *Finder) XattrEquals(name, value string) *Finder {
+	if f.lastErr != nil { return f }
+	f.addXattrFilter("XattrEquals", func(xattrs map[string][]byte) bool {
+		attrValue, found := xattrs[name]
+		return found && bytes.Equal(attrValue, []byte(value))
+	})
+	return f
+}
+
+// XattrRegexp adds matching against value of extended attribute using regexp pattern. Files without attribute aren't
+// matched.
+func (f *Finder) XattrRegexp(name, pattern string) *Finder {
+	if f.lastErr != nil { return f }
+	var compiled *regexp.Regexp
+	if compiled, f.lastErr = regexp.Compile(pattern); f.lastErr != nil {
+		return f
+	}
+	f.addXattrFilter("XattrRegexp", func(xattrs map[string][]byte) bool {
+		attrValue, found := xattrs[name]
+		return found && compiled.Match(attrValue)
+	})
+	return f
+}
+
+// HasACL adds matching of files with POSIX ACL (access or default) beyond regular permission bits
+func (f *Finder) HasACL() *Finder {
+	if f.lastErr != nil { return f }
+	f.addXattrFilter("HasACL", func(xattrs map[string][]byte) bool {
+		_, access := xattrs[file.XattrACLAccess]
+		_, defaultACL := xattrs[file.XattrACLDefault]
+		return access || defaultACL
+	})
+	return f
+}
@@ -0,0 +1,28 @@
+package finder
+
+import (
+	"testing"
+
+	"github.com/duffpl/go-finder/file"
+)
+
+func TestFinder_XattrFilters(t *testing.T) {
+	mockGlob := newMockGlobFunc([]file.FileInfoEx{
+		withAttr("tagged", xattrsAttr, map[string][]byte{
+			"user.origin": []byte("upload-2024"),
+		}),
+		withAttr("other-tag", xattrsAttr, map[string][]byte{
+			"user.origin": []byte("backup"),
+		}),
+		withAttr("acl", xattrsAttr, map[string][]byte{
+			file.XattrACLDefault: {2, 0, 0, 0},
+		}),
+		withAttr("plain", xattrsAttr, map[string][]byte{}),
+	})
+	runFilterExpectations(t, mockGlob, "test-glob", []filterExpectation{
+		{"HasXattr", New().HasXattr("user.origin"), []string{"other-tag", "tagged"}},
+		{"XattrEquals", New().XattrEquals("user.origin", "backup"), []string{"other-tag"}},
+		{"XattrRegexp", New().XattrRegexp("user.origin", "^upload-"), []string{"tagged"}},
+		{"HasACL", New().HasACL(), []string{"acl"}},
+	})
+}